@@ -0,0 +1,250 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/nickwells/semver.mod/v3/semver"
+)
+
+// svJSON is the form in which the parse subcommand prints a version as
+// JSON. The version numbers are given as json.Numbers so that numbers too
+// big for an int are printed exactly.
+type svJSON struct {
+	Version   string      `json:"version"`
+	Major     json.Number `json:"major"`
+	Minor     json.Number `json:"minor"`
+	Patch     json.Number `json:"patch"`
+	PreRelIDs []string    `json:"preRelIDs,omitempty"`
+	BuildIDs  []string    `json:"buildIDs,omitempty"`
+}
+
+// cmdParse prints the parts of the version
+func cmdParse(e *env, args []string) int {
+	fs := e.flagSet("parse", "version",
+		"Print the parts of the version, one per line.")
+	asJSON := fs.Bool("json", false, "print the parts as a JSON object")
+
+	if code, ok := e.parseFlags(fs, args, 1, 1); !ok {
+		return code
+	}
+
+	sv, err := e.parse(fs.Arg(0))
+	if err != nil {
+		return e.errorf("%v", err)
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(e.stdout)
+		enc.SetIndent("", "  ")
+
+		err := enc.Encode(svJSON{
+			Version:   e.format(sv),
+			Major:     json.Number(sv.MajorBig().String()),
+			Minor:     json.Number(sv.MinorBig().String()),
+			Patch:     json.Number(sv.PatchBig().String()),
+			PreRelIDs: sv.PreRelIDs(),
+			BuildIDs:  sv.BuildIDs(),
+		})
+		if err != nil {
+			return e.errorf("cannot write the JSON: %v", err)
+		}
+
+		return exitOK
+	}
+
+	for _, f := range []struct{ name, val string }{
+		{"version", e.format(sv)},
+		{"major", sv.MajorBig().String()},
+		{"minor", sv.MinorBig().String()},
+		{"patch", sv.PatchBig().String()},
+		{"pre-release", strings.Join(sv.PreRelIDs(), ".")},
+		{"build", strings.Join(sv.BuildIDs(), ".")},
+	} {
+		fmt.Fprintf(e.stdout, "%-12s %s\n", f.name+":", f.val)
+	}
+
+	return exitOK
+}
+
+// cmdValidate checks that each version is well-formed
+func cmdValidate(e *env, args []string) int {
+	fs := e.flagSet("validate", "version ...",
+		"Check that each version is well-formed. The problem with each"+
+			" bad version is\nreported and the exit status is 1 if any"+
+			" version is bad.")
+	quiet := fs.Bool("q", false, "do not report the problems")
+
+	if code, ok := e.parseFlags(fs, args, 1, -1); !ok {
+		return code
+	}
+
+	code := exitOK
+
+	for _, s := range fs.Args() {
+		if _, err := e.parse(s); err != nil {
+			code = exitFalse
+
+			if !*quiet {
+				e.reportBadSV(err)
+			}
+		}
+	}
+
+	return code
+}
+
+// cmdCompare prints the result of comparing the two versions
+func cmdCompare(e *env, args []string) int {
+	fs := e.flagSet("compare", "version1 version2",
+		"Print -1, 0 or 1 as version1 is less than, equal to or greater"+
+			" than version2.\nBy default the build IDs are ignored.")
+	withBuild := fs.Bool("build", false,
+		"compare the build IDs of versions with the same precedence")
+
+	if code, ok := e.parseFlags(fs, args, 2, 2); !ok {
+		return code
+	}
+
+	svl, err := e.parseAll(fs.Args())
+	if err != nil {
+		return e.errorf("%v", err)
+	}
+
+	cmpFunc := semver.Compare
+	if *withBuild {
+		cmpFunc = semver.CompareWithBuild
+	}
+
+	fmt.Fprintln(e.stdout, max(-1, min(1, cmpFunc(svl[0], svl[1]))))
+
+	return exitOK
+}
+
+// cmdSort sorts the versions read from the standard input
+func cmdSort(e *env, args []string) int {
+	fs := e.flagSet("sort", "",
+		"Read versions from the standard input, one per line, and print"+
+			" them in order.\nBlank lines are ignored.")
+	reverse := fs.Bool("r", false, "print the versions in descending order")
+
+	if code, ok := e.parseFlags(fs, args, 0, 0); !ok {
+		return code
+	}
+
+	strs, err := e.readVersions()
+	if err != nil {
+		return e.errorf("%v", err)
+	}
+
+	svl, err := e.parseAll(strs)
+	if err != nil {
+		return e.errorf("%v", err)
+	}
+
+	if *reverse {
+		sort.Stable(sort.Reverse(svl))
+	} else {
+		sort.Stable(svl)
+	}
+
+	for _, sv := range svl {
+		fmt.Fprintln(e.stdout, e.format(sv))
+	}
+
+	return exitOK
+}
+
+// cmdBump prints the version with the given part incremented
+func cmdBump(e *env, args []string) int {
+	fs := e.flagSet("bump", "major|minor|patch|prerelease version",
+		"Print the version with the given part incremented. The build IDs"+
+			" are kept.")
+	channel := fs.String("channel", "",
+		"the pre-release channel (such as alpha or rc) for a prerelease"+
+			" bump")
+
+	if code, ok := e.parseFlags(fs, args, 2, 2); !ok {
+		return code
+	}
+
+	part := fs.Arg(0)
+	if *channel != "" && part != "prerelease" {
+		return e.errorf("the -channel flag can only be given with prerelease")
+	}
+
+	sv, err := e.parse(fs.Arg(1))
+	if err != nil {
+		return e.errorf("%v", err)
+	}
+
+	switch part {
+	case "major":
+		sv.IncrMajor()
+	case "minor":
+		sv.IncrMinor()
+	case "patch":
+		sv.IncrPatch()
+	case "prerelease":
+		if err := sv.IncrPreRelease(*channel); err != nil {
+			return e.errorf("%v", err)
+		}
+	default:
+		return e.errorf("bad part to bump: %q"+
+			" - it must be major, minor, patch or prerelease", part)
+	}
+
+	fmt.Fprintln(e.stdout, e.format(sv))
+
+	return exitOK
+}
+
+// cmdSatisfies prints those versions which satisfy the constraint
+func cmdSatisfies(e *env, args []string) int {
+	fs := e.flagSet("satisfies", "constraint [version ...]",
+		"Print those versions which satisfy the constraint. If no versions"+
+			" are given\nthey are read from the standard input, one per"+
+			" line. The exit status is 1\nif no version satisfies the"+
+			" constraint.")
+	cargo := fs.Bool("cargo", false,
+		"the constraint is in the Cargo dialect rather than npm")
+
+	if code, ok := e.parseFlags(fs, args, 1, -1); !ok {
+		return code
+	}
+
+	d := semver.DialectNPM
+	if *cargo {
+		d = semver.DialectCargo
+	}
+
+	c, err := semver.ParseConstraintDialect(fs.Arg(0), d)
+	if err != nil {
+		return e.errorf("%v", err)
+	}
+
+	strs := fs.Args()[1:]
+	if len(strs) == 0 {
+		if strs, err = e.readVersions(); err != nil {
+			return e.errorf("%v", err)
+		}
+	}
+
+	svl, err := e.parseAll(strs)
+	if err != nil {
+		return e.errorf("%v", err)
+	}
+
+	matches := svl.Filter(c.Check)
+	for _, sv := range matches {
+		fmt.Fprintln(e.stdout, e.format(sv))
+	}
+
+	if len(matches) == 0 {
+		return exitFalse
+	}
+
+	return exitOK
+}
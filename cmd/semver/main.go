@@ -0,0 +1,226 @@
+/*
+The semver command gives access to the semver package from shell scripts
+and Makefiles. It has subcommands to parse, validate, compare, sort and
+bump semantic version IDs and to check them against a constraint.
+
+The versions must start with a 'v' unless the -strict flag is given in
+which case they must not; versions are printed in the same form.
+
+The exit status is 0 on success, 1 if a version is invalid (validate) or
+no version satisfies the constraint (satisfies) and 2 for any other error.
+*/
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/nickwells/semver.mod/v3/semver"
+)
+
+const progName = "semver"
+
+// These are the exit statuses of the command
+const (
+	exitOK    = 0
+	exitFalse = 1
+	exitError = 2
+)
+
+// subCmd describes one of the subcommands
+type subCmd struct {
+	name string
+	desc string
+	f    func(e *env, args []string) int
+}
+
+// subCmds lists the subcommands in the order they are shown in the usage
+// message
+var subCmds = []subCmd{
+	{"parse", "print the parts of a version", cmdParse},
+	{"validate", "check that versions are well-formed", cmdValidate},
+	{"compare", "compare two versions", cmdCompare},
+	{"sort", "sort the versions read from the standard input", cmdSort},
+	{"bump", "increment part of a version", cmdBump},
+	{"satisfies", "print the versions satisfying a constraint", cmdSatisfies},
+}
+
+// env holds the input and output streams and the settings common to all
+// the subcommands
+type env struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	strict bool
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run runs the subcommand named by the first argument and returns the exit
+// status
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	e := &env{stdin: stdin, stdout: stdout, stderr: stderr}
+
+	if len(args) == 0 {
+		e.usage()
+
+		return exitError
+	}
+
+	switch args[0] {
+	case "help", "-h", "-help", "--help":
+		e.stderr = e.stdout
+		e.usage()
+
+		return exitOK
+	}
+
+	for _, sc := range subCmds {
+		if sc.name == args[0] {
+			return sc.f(e, args[1:])
+		}
+	}
+
+	fmt.Fprintf(e.stderr, "%s: unknown subcommand: %q\n", progName, args[0])
+	e.usage()
+
+	return exitError
+}
+
+// usage prints the top-level usage message
+func (e *env) usage() {
+	fmt.Fprintf(e.stderr, "usage: %s subcommand [flags] [args]\n\n", progName)
+	fmt.Fprintln(e.stderr, "The subcommands are:")
+
+	for _, sc := range subCmds {
+		fmt.Fprintf(e.stderr, "\t%-10s %s\n", sc.name, sc.desc)
+	}
+
+	fmt.Fprintf(e.stderr,
+		"\nUse \"%s subcommand -help\" for more information.\n", progName)
+}
+
+// flagSet returns a FlagSet for the subcommand with the flags common to all
+// the subcommands already added
+func (e *env) flagSet(name, argDesc, desc string) *flag.FlagSet {
+	fs := flag.NewFlagSet(progName+" "+name, flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	fs.BoolVar(&e.strict, "strict", false,
+		"the versions have no leading 'v'")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: %s %s [flags] %s\n\n%s\n\n",
+			progName, name, argDesc, desc)
+		fs.PrintDefaults()
+	}
+
+	return fs
+}
+
+// parseFlags parses the arguments and checks that the number of remaining
+// arguments is between minArgs and maxArgs; a negative maxArgs means there
+// is no limit. If it returns false the subcommand should stop and return
+// the exit status.
+func (e *env) parseFlags(fs *flag.FlagSet, args []string,
+	minArgs, maxArgs int,
+) (int, bool) {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK, false
+		}
+
+		return exitError, false
+	}
+
+	if fs.NArg() < minArgs || (maxArgs >= 0 && fs.NArg() > maxArgs) {
+		fmt.Fprintf(e.stderr, "%s: wrong number of arguments: %d\n",
+			fs.Name(), fs.NArg())
+		fs.Usage()
+
+		return exitError, false
+	}
+
+	return exitOK, true
+}
+
+// errorf reports the error and returns the error exit status
+func (e *env) errorf(format string, args ...any) int {
+	fmt.Fprintf(e.stderr, progName+": "+format+"\n", args...)
+
+	return exitError
+}
+
+// parse parses the version, with or without the leading 'v' according to
+// the strict setting
+func (e *env) parse(s string) (*semver.SV, error) {
+	if e.strict {
+		return semver.ParseStrictSV(s)
+	}
+
+	return semver.ParseSV(s)
+}
+
+// format returns the version as a string, with or without the leading 'v'
+// according to the strict setting
+func (e *env) format(sv *semver.SV) string {
+	if e.strict {
+		return strings.TrimPrefix(sv.String(), "v")
+	}
+
+	return sv.String()
+}
+
+// parseAll parses each of the versions and returns them as an SVList. It
+// returns an error for the first bad version.
+func (e *env) parseAll(strs []string) (semver.SVList, error) {
+	svl := make(semver.SVList, 0, len(strs))
+
+	for _, s := range strs {
+		sv, err := e.parse(s)
+		if err != nil {
+			return nil, err
+		}
+
+		svl = append(svl, sv)
+	}
+
+	return svl, nil
+}
+
+// readVersions returns the non-blank lines read from the standard input
+// with any surrounding white space removed
+func (e *env) readVersions() ([]string, error) {
+	var strs []string
+
+	scanner := bufio.NewScanner(e.stdin)
+	for scanner.Scan() {
+		s := strings.TrimSpace(scanner.Text())
+		if s != "" {
+			strs = append(strs, s)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("cannot read the standard input: %w", err)
+	}
+
+	return strs, nil
+}
+
+// reportBadSV reports the error from parsing a version. If it is a
+// ParseError the input is shown with the bad part marked.
+func (e *env) reportBadSV(err error) {
+	fmt.Fprintf(e.stderr, "%s: %v\n", progName, err)
+
+	var pe *semver.ParseError
+	if errors.As(err, &pe) {
+		fmt.Fprintf(e.stderr, "\t%s\n\t%s^ the %s part is bad\n",
+			pe.Input, strings.Repeat(" ", pe.Offset), pe.Part)
+	}
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestRun(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		args      []string
+		stdin     string
+		expCode   int
+		expStdout string
+		stdoutPfx bool
+		expStderr []string
+	}{
+		{
+			ID:        testhelper.MkID("no args"),
+			expCode:   exitError,
+			expStderr: []string{"usage: semver subcommand"},
+		},
+		{
+			ID:        testhelper.MkID("help"),
+			args:      []string{"help"},
+			expCode:   exitOK,
+			expStdout: "usage: semver subcommand",
+			stdoutPfx: true,
+		},
+		{
+			ID:        testhelper.MkID("unknown subcommand"),
+			args:      []string{"nonesuch"},
+			expCode:   exitError,
+			expStderr: []string{`unknown subcommand: "nonesuch"`},
+		},
+		{
+			ID:        testhelper.MkID("subcommand help"),
+			args:      []string{"bump", "-help"},
+			expCode:   exitOK,
+			expStderr: []string{"usage: semver bump", "-channel"},
+		},
+		{
+			ID:      testhelper.MkID("parse"),
+			args:    []string{"parse", "v1.2.3-rc.1+b.5"},
+			expCode: exitOK,
+			expStdout: "version:     v1.2.3-rc.1+b.5\n" +
+				"major:       1\n" +
+				"minor:       2\n" +
+				"patch:       3\n" +
+				"pre-release: rc.1\n" +
+				"build:       b.5\n",
+		},
+		{
+			ID: testhelper.MkID("parse - JSON, strict, big"),
+			args: []string{
+				"parse", "-json", "-strict",
+				"123456789012345678901234567890.0.1-rc",
+			},
+			expCode: exitOK,
+			expStdout: "{\n" +
+				`  "version": "123456789012345678901234567890.0.1-rc",` +
+				"\n" +
+				`  "major": 123456789012345678901234567890,` + "\n" +
+				`  "minor": 0,` + "\n" +
+				`  "patch": 1,` + "\n" +
+				`  "preRelIDs": [` + "\n" +
+				`    "rc"` + "\n" +
+				"  ]\n" +
+				"}\n",
+		},
+		{
+			ID:        testhelper.MkID("parse - bad"),
+			args:      []string{"parse", "1.2.3"},
+			expCode:   exitError,
+			expStderr: []string{"does not start with a 'v'"},
+		},
+		{
+			ID:        testhelper.MkID("parse - too many args"),
+			args:      []string{"parse", "v1.2.3", "v1.2.4"},
+			expCode:   exitError,
+			expStderr: []string{"wrong number of arguments: 2"},
+		},
+		{
+			ID:      testhelper.MkID("validate - good"),
+			args:    []string{"validate", "v1.2.3", "v0.0.1-alpha"},
+			expCode: exitOK,
+		},
+		{
+			ID:      testhelper.MkID("validate - bad"),
+			args:    []string{"validate", "v1.2.3", "v1.02.3"},
+			expCode: exitFalse,
+			expStderr: []string{
+				`the minor version: "02" has a leading 0`,
+				"\tv1.02.3\n\t   ^ the minor part is bad\n",
+			},
+		},
+		{
+			ID:      testhelper.MkID("validate - bad, quiet"),
+			args:    []string{"validate", "-q", "1.2.3"},
+			expCode: exitFalse,
+		},
+		{
+			ID:        testhelper.MkID("compare - less"),
+			args:      []string{"compare", "v1.0.0-rc.1", "v1.0.0"},
+			expCode:   exitOK,
+			expStdout: "-1\n",
+		},
+		{
+			ID:        testhelper.MkID("compare - equal"),
+			args:      []string{"compare", "v1.0.0+a", "v1.0.0+b"},
+			expCode:   exitOK,
+			expStdout: "0\n",
+		},
+		{
+			ID:        testhelper.MkID("compare - with build"),
+			args:      []string{"compare", "-build", "v1.0.0+b", "v1.0.0+a"},
+			expCode:   exitOK,
+			expStdout: "1\n",
+		},
+		{
+			ID:        testhelper.MkID("sort"),
+			args:      []string{"sort"},
+			stdin:     "v1.10.0\n  v1.2.0\n\nv1.2.0-rc.1\nv0.9.9\n",
+			expCode:   exitOK,
+			expStdout: "v0.9.9\nv1.2.0-rc.1\nv1.2.0\nv1.10.0\n",
+		},
+		{
+			ID:        testhelper.MkID("sort - reversed, strict"),
+			args:      []string{"sort", "-r", "-strict"},
+			stdin:     "1.10.0\n1.2.0\n",
+			expCode:   exitOK,
+			expStdout: "1.10.0\n1.2.0\n",
+		},
+		{
+			ID:        testhelper.MkID("sort - bad"),
+			args:      []string{"sort"},
+			stdin:     "v1.10.0\nv1.2\n",
+			expCode:   exitError,
+			expStderr: []string{"cannot be split into major/minor/patch"},
+		},
+		{
+			ID:        testhelper.MkID("bump major"),
+			args:      []string{"bump", "major", "v1.2.3-rc.1+b.1"},
+			expCode:   exitOK,
+			expStdout: "v2.0.0+b.1\n",
+		},
+		{
+			ID:        testhelper.MkID("bump minor"),
+			args:      []string{"bump", "minor", "v1.2.3"},
+			expCode:   exitOK,
+			expStdout: "v1.3.0\n",
+		},
+		{
+			ID:        testhelper.MkID("bump patch"),
+			args:      []string{"bump", "-strict", "patch", "1.2.3"},
+			expCode:   exitOK,
+			expStdout: "1.2.4\n",
+		},
+		{
+			ID:        testhelper.MkID("bump prerelease"),
+			args:      []string{"bump", "prerelease", "v1.2.3-rc.1"},
+			expCode:   exitOK,
+			expStdout: "v1.2.3-rc.2\n",
+		},
+		{
+			ID: testhelper.MkID("bump prerelease - channel"),
+			args: []string{
+				"bump", "-channel", "beta", "prerelease", "v1.2.3",
+			},
+			expCode:   exitOK,
+			expStdout: "v1.2.4-beta.0\n",
+		},
+		{
+			ID:        testhelper.MkID("bump - channel not prerelease"),
+			args:      []string{"bump", "-channel", "rc", "major", "v1.2.3"},
+			expCode:   exitError,
+			expStderr: []string{"can only be given with prerelease"},
+		},
+		{
+			ID:        testhelper.MkID("bump - bad part"),
+			args:      []string{"bump", "micro", "v1.2.3"},
+			expCode:   exitError,
+			expStderr: []string{`bad part to bump: "micro"`},
+		},
+		{
+			ID:        testhelper.MkID("satisfies - args"),
+			args:      []string{"satisfies", "^1.2", "v1.3.0", "v2.0.0"},
+			expCode:   exitOK,
+			expStdout: "v1.3.0\n",
+		},
+		{
+			ID:        testhelper.MkID("satisfies - stdin"),
+			args:      []string{"satisfies", ">=1.2.0 <2.0.0"},
+			stdin:     "v1.1.0\nv1.5.0\nv1.2.0\nv2.0.0\n",
+			expCode:   exitOK,
+			expStdout: "v1.5.0\nv1.2.0\n",
+		},
+		{
+			ID:        testhelper.MkID("satisfies - cargo"),
+			args:      []string{"satisfies", "-cargo", "1.2, <1.5", "v1.4.9"},
+			expCode:   exitOK,
+			expStdout: "v1.4.9\n",
+		},
+		{
+			ID:      testhelper.MkID("satisfies - none"),
+			args:    []string{"satisfies", "^1.2", "v2.0.0"},
+			expCode: exitFalse,
+		},
+		{
+			ID:        testhelper.MkID("satisfies - bad constraint"),
+			args:      []string{"satisfies", ">>1", "v2.0.0"},
+			expCode:   exitError,
+			expStderr: []string{"bad version constraint"},
+		},
+	}
+
+	for _, tc := range testCases {
+		var stdout, stderr bytes.Buffer
+
+		code := run(tc.args, strings.NewReader(tc.stdin), &stdout, &stderr)
+		testhelper.DiffInt(t, tc.IDStr(), "exit status", code, tc.expCode)
+
+		if tc.stdoutPfx {
+			if !strings.HasPrefix(stdout.String(), tc.expStdout) {
+				t.Log(tc.IDStr())
+				t.Errorf("\t: stdout should start with: %q, got: %q",
+					tc.expStdout, stdout.String())
+			}
+		} else {
+			testhelper.DiffString(t, tc.IDStr(), "stdout",
+				stdout.String(), tc.expStdout)
+		}
+
+		for _, s := range tc.expStderr {
+			if !strings.Contains(stderr.String(), s) {
+				t.Log(tc.IDStr())
+				t.Errorf("\t: stderr should contain: %q, got: %q",
+					s, stderr.String())
+			}
+		}
+
+		if len(tc.expStderr) == 0 && tc.expCode == exitOK &&
+			stderr.Len() != 0 {
+			t.Log(tc.IDStr())
+			t.Errorf("\t: unexpected stderr: %q", stderr.String())
+		}
+	}
+}
@@ -0,0 +1,572 @@
+/*
+Package apidiff compares the exported API of two versions of a Go package
+and checks that a change of semantic version is big enough for the changes
+made. The packages are loaded from directories on disk and type-checked
+using go/types; they must type-check without errors.
+
+Each change to the API is classified as incompatible (such as removing an
+exported name or changing the type of a function), a compatible addition
+(such as adding an exported name, struct field or method) or no change.
+Renaming the parameters or results of a func is not a change.
+An incompatible change needs a new major version, a compatible addition
+needs at least a new minor version and otherwise a new patch version is
+enough. While the major version number is 0 there is no promise of
+compatibility and an incompatible change only needs a new minor version.
+*/
+package apidiff
+
+import (
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/nickwells/semver.mod/v3/semver"
+)
+
+// Kind classifies a change to the API
+type Kind int
+
+// These are the kinds of change in increasing order of severity
+const (
+	KindNone Kind = iota
+	KindCompatible
+	KindIncompatible
+)
+
+// String returns the name of the Kind
+func (k Kind) String() string {
+	switch k {
+	case KindNone:
+		return "none"
+	case KindCompatible:
+		return "compatible"
+	case KindIncompatible:
+		return "incompatible"
+	}
+
+	return fmt.Sprintf("Kind(%d)", int(k))
+}
+
+// Change records a change to the exported API
+type Change struct {
+	// Name is the name of the changed item. A field or method is given as
+	// the type name and the field or method name separated by a '.'
+	Name string
+	// Kind is the kind of the change
+	Kind Kind
+	// Desc describes the change
+	Desc string
+}
+
+// String returns a description of the Change
+func (c Change) String() string {
+	return fmt.Sprintf("%s: %s (%s)", c.Name, c.Desc, c.Kind)
+}
+
+// Report records the changes between two versions of a package
+type Report struct {
+	// Changes holds the changes to the API, sorted by name
+	Changes []Change
+}
+
+// Kind returns the most severe kind of change in the Report
+func (r Report) Kind() Kind {
+	k := KindNone
+	for _, c := range r.Changes {
+		k = max(k, c.Kind)
+	}
+
+	return k
+}
+
+// Compare loads the Go packages in the two directories and returns a
+// Report of the changes to the exported API from the old package to the
+// new. The test files are ignored. It returns an error if either package
+// cannot be loaded.
+func Compare(oldDir, newDir string) (Report, error) {
+	oldPkg, err := load(oldDir)
+	if err != nil {
+		return Report{}, err
+	}
+
+	newPkg, err := load(newDir)
+	if err != nil {
+		return Report{}, err
+	}
+
+	return comparePkgs(oldPkg, newPkg), nil
+}
+
+// load parses and type-checks the Go package in the directory. Imported
+// packages are loaded from source, relative to the directory, so that
+// packages from other modules can be found.
+func load(dir string) (*types.Package, error) {
+	bp, err := build.ImportDir(dir, 0)
+	if err != nil {
+		return nil, fmt.Errorf("cannot find the Go package in %q: %w", dir, err)
+	}
+
+	fset := token.NewFileSet()
+	files := make([]*ast.File, 0, len(bp.GoFiles))
+
+	for _, name := range bp.GoFiles {
+		f, err := parser.ParseFile(fset, filepath.Join(dir, name), nil,
+			parser.SkipObjectResolution)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse the Go package in %q: %w",
+				dir, err)
+		}
+
+		files = append(files, f)
+	}
+
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+
+	pkg, err := conf.Check(bp.Name, fset, files, nil)
+	if err != nil {
+		return nil, fmt.Errorf("cannot type-check the Go package in %q: %w",
+			dir, err)
+	}
+
+	return pkg, nil
+}
+
+// differ accumulates the changes between two packages
+type differ struct {
+	oldPkg, newPkg *types.Package
+	changes        []Change
+}
+
+// add records a change
+func (d *differ) add(name string, k Kind, format string, args ...any) {
+	d.changes = append(d.changes,
+		Change{Name: name, Kind: k, Desc: fmt.Sprintf(format, args...)})
+}
+
+// oldStr returns the string form of a type from the old package. Types
+// from the package itself are not qualified so that they can be compared
+// with those from the new package.
+func (d *differ) oldStr(t types.Type) string { return typeString(d.oldPkg, t) }
+
+// newStr returns the string form of a type from the new package
+func (d *differ) newStr(t types.Type) string { return typeString(d.newPkg, t) }
+
+// typeString returns the string form of the type, relative to the
+// package. The names of the parameters and results of any func types are
+// left out since renaming them does not affect the users of the package.
+func typeString(pkg *types.Package, t types.Type) string {
+	qf := types.RelativeTo(pkg)
+
+	sig, ok := t.(*types.Signature)
+	if !ok || sig.TypeParams().Len() == 0 {
+		return types.TypeString(unnamed(t), qf)
+	}
+
+	// a generic func: the type parameters cannot be given to a new
+	// signature so they are added to the string
+	s := types.TypeString(unnamed(t), qf)
+
+	return "func" + typeParamsStr(pkg, sig.TypeParams()) +
+		strings.TrimPrefix(s, "func")
+}
+
+// unnamed returns the type with the parameter and result names removed
+// from any func types it is made from. The type parameters of a generic
+// func are also removed. Other types are returned unchanged.
+func unnamed(t types.Type) types.Type {
+	switch t := t.(type) {
+	case *types.Signature:
+		return types.NewSignatureType(nil, nil, nil,
+			unnamedTuple(t.Params()), unnamedTuple(t.Results()),
+			t.Variadic())
+	case *types.Pointer:
+		return types.NewPointer(unnamed(t.Elem()))
+	case *types.Slice:
+		return types.NewSlice(unnamed(t.Elem()))
+	case *types.Array:
+		return types.NewArray(unnamed(t.Elem()), t.Len())
+	case *types.Map:
+		return types.NewMap(unnamed(t.Key()), unnamed(t.Elem()))
+	case *types.Chan:
+		return types.NewChan(t.Dir(), unnamed(t.Elem()))
+	}
+
+	return t
+}
+
+// unnamedTuple returns the tuple of parameters or results without names
+func unnamedTuple(tup *types.Tuple) *types.Tuple {
+	vars := make([]*types.Var, 0, tup.Len())
+	for v := range tup.Variables() {
+		vars = append(vars,
+			types.NewParam(v.Pos(), v.Pkg(), "", unnamed(v.Type())))
+	}
+
+	return types.NewTuple(vars...)
+}
+
+// comparePkgs returns a Report of the changes to the exported API
+func comparePkgs(oldPkg, newPkg *types.Package) Report {
+	d := &differ{oldPkg: oldPkg, newPkg: newPkg}
+	oldScope, newScope := oldPkg.Scope(), newPkg.Scope()
+
+	for _, name := range oldScope.Names() {
+		if !token.IsExported(name) {
+			continue
+		}
+
+		oldObj := oldScope.Lookup(name)
+
+		newObj := newScope.Lookup(name)
+		if newObj == nil {
+			d.add(name, KindIncompatible, "%s removed", objKind(oldObj))
+
+			continue
+		}
+
+		d.compareObjs(name, oldObj, newObj)
+	}
+
+	for _, name := range newScope.Names() {
+		if token.IsExported(name) && oldScope.Lookup(name) == nil {
+			d.add(name, KindCompatible, "%s added",
+				objKind(newScope.Lookup(name)))
+		}
+	}
+
+	slices.SortStableFunc(d.changes, func(a, b Change) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return Report{Changes: d.changes}
+}
+
+// objKind returns a description of the kind of the object
+func objKind(obj types.Object) string {
+	switch obj.(type) {
+	case *types.Const:
+		return "const"
+	case *types.Var:
+		return "var"
+	case *types.Func:
+		return "func"
+	case *types.TypeName:
+		return "type"
+	}
+
+	return "object"
+}
+
+// compareObjs records the changes between two package-level objects
+func (d *differ) compareObjs(name string, oldObj, newObj types.Object) {
+	if objKind(oldObj) != objKind(newObj) {
+		d.add(name, KindIncompatible, "changed from a %s to a %s",
+			objKind(oldObj), objKind(newObj))
+
+		return
+	}
+
+	if oldTN, ok := oldObj.(*types.TypeName); ok {
+		d.compareTypes(name, oldTN, newObj.(*types.TypeName))
+
+		return
+	}
+
+	oldT, newT := d.oldStr(oldObj.Type()), d.newStr(newObj.Type())
+	if oldT != newT {
+		d.add(name, KindIncompatible, "type changed from %s to %s",
+			oldT, newT)
+
+		return
+	}
+
+	if oldC, ok := oldObj.(*types.Const); ok {
+		oldV, newV := oldC.Val(), newObj.(*types.Const).Val()
+		if oldV.ExactString() != newV.ExactString() {
+			d.add(name, KindIncompatible, "value changed from %s to %s",
+				oldV, newV)
+		}
+	}
+}
+
+// compareTypes records the changes between two type declarations
+func (d *differ) compareTypes(name string, oldTN, newTN *types.TypeName) {
+	if oldTN.IsAlias() || newTN.IsAlias() {
+		oldT, newT := typeDesc(d.oldPkg, oldTN), typeDesc(d.newPkg, newTN)
+		if oldT != newT {
+			d.add(name, KindIncompatible, "changed from %s to %s",
+				oldT, newT)
+		}
+
+		return
+	}
+
+	oldNamed, okOld := oldTN.Type().(*types.Named)
+	newNamed, okNew := newTN.Type().(*types.Named)
+
+	if !okOld || !okNew {
+		return
+	}
+
+	oldTP, newTP := typeParamsStr(d.oldPkg, oldNamed.TypeParams()),
+		typeParamsStr(d.newPkg, newNamed.TypeParams())
+	if oldTP != newTP {
+		d.add(name, KindIncompatible, "type parameters changed from %s to %s",
+			oldTP, newTP)
+
+		return
+	}
+
+	switch oldU := oldNamed.Underlying().(type) {
+	case *types.Struct:
+		if newU, ok := newNamed.Underlying().(*types.Struct); ok {
+			d.compareStructs(name, oldU, newU)
+			d.compareMethods(name, oldNamed, newNamed)
+
+			return
+		}
+	case *types.Interface:
+		if newU, ok := newNamed.Underlying().(*types.Interface); ok {
+			d.compareInterfaces(name, oldU, newU)
+
+			return
+		}
+	default:
+		oldUStr := d.oldStr(oldU)
+		newUStr := d.newStr(newNamed.Underlying())
+
+		if oldUStr == newUStr {
+			d.compareMethods(name, oldNamed, newNamed)
+
+			return
+		}
+	}
+
+	d.add(name, KindIncompatible, "underlying type changed from %s to %s",
+		d.oldStr(oldNamed.Underlying()), d.newStr(newNamed.Underlying()))
+}
+
+// typeDesc returns a description of the type declaration, showing the
+// aliased type for an alias and the underlying type otherwise
+func typeDesc(pkg *types.Package, tn *types.TypeName) string {
+	if tn.IsAlias() {
+		return "an alias of " + typeString(pkg, types.Unalias(tn.Type()))
+	}
+
+	return "a defined type with underlying type " +
+		typeString(pkg, tn.Type().Underlying())
+}
+
+// typeParamsStr returns the type parameters as a string
+func typeParamsStr(pkg *types.Package, tps *types.TypeParamList) string {
+	if tps.Len() == 0 {
+		return "[]"
+	}
+
+	parts := make([]string, 0, tps.Len())
+	for tp := range tps.TypeParams() {
+		parts = append(parts, tp.Obj().Name()+" "+
+			typeString(pkg, tp.Constraint()))
+	}
+
+	return "[" + strings.Join(parts, ", ") + "]"
+}
+
+// exportedFields returns the exported fields of the struct as a map from
+// name to type string. An embedded field is named by its type.
+func exportedFields(pkg *types.Package, s *types.Struct) map[string]string {
+	fields := map[string]string{}
+
+	for f := range s.Fields() {
+		if f.Exported() {
+			fields[f.Name()] = typeString(pkg, f.Type())
+		}
+	}
+
+	return fields
+}
+
+// compareStructs records the changes to the exported fields of a struct
+func (d *differ) compareStructs(name string, oldS, newS *types.Struct) {
+	d.compareMembers(name, "field",
+		exportedFields(d.oldPkg, oldS), exportedFields(d.newPkg, newS),
+		KindCompatible)
+}
+
+// methods returns the exported methods in the method set of the type,
+// including those with pointer receivers, as a map from name to signature
+func methods(pkg *types.Package, t types.Type) map[string]string {
+	ms := map[string]string{}
+
+	mset := types.NewMethodSet(t)
+	for sel := range mset.Methods() {
+		if sel.Obj().Exported() {
+			ms[sel.Obj().Name()] = typeString(pkg, sel.Type())
+		}
+	}
+
+	return ms
+}
+
+// compareMethods records the changes to the methods of a named type
+func (d *differ) compareMethods(name string, oldT, newT *types.Named) {
+	d.compareMembers(name, "method",
+		methods(d.oldPkg, types.NewPointer(oldT)),
+		methods(d.newPkg, types.NewPointer(newT)),
+		KindCompatible)
+}
+
+// compareInterfaces records the changes to the methods of an interface.
+// Adding a method is incompatible, since existing implementations will no
+// longer satisfy the interface, unless the interface has an unexported
+// method and so cannot be implemented outside the package. For the same
+// reason adding the first unexported method is incompatible.
+func (d *differ) compareInterfaces(name string, oldI, newI *types.Interface) {
+	addedKind := KindIncompatible
+
+	for m := range oldI.Methods() {
+		if !m.Exported() {
+			addedKind = KindCompatible
+
+			break
+		}
+	}
+
+	if addedKind == KindIncompatible {
+		for m := range newI.Methods() {
+			if !m.Exported() {
+				d.add(name+"."+m.Name(), KindIncompatible,
+					"unexported method added")
+			}
+		}
+	}
+
+	d.compareMembers(name, "method",
+		methods(d.oldPkg, oldI), methods(d.newPkg, newI), addedKind)
+}
+
+// compareMembers records the changes between the old and new members
+// (fields or methods) of a type. A removed or changed member is an
+// incompatible change; an added member has the given kind.
+func (d *differ) compareMembers(typeName, desc string,
+	oldMembers, newMembers map[string]string, addedKind Kind,
+) {
+	for name, oldT := range oldMembers {
+		fullName := typeName + "." + name
+
+		newT, ok := newMembers[name]
+		switch {
+		case !ok:
+			d.add(fullName, KindIncompatible, "%s removed", desc)
+		case oldT != newT:
+			d.add(fullName, KindIncompatible, "type changed from %s to %s",
+				oldT, newT)
+		}
+	}
+
+	for name := range newMembers {
+		if _, ok := oldMembers[name]; !ok {
+			d.add(typeName+"."+name, addedKind, "%s added", desc)
+		}
+	}
+}
+
+// Suggest returns the lowest version following the old version which is a
+// big enough change for the changes in the Report. The pre-release and
+// build IDs are removed.
+func (r Report) Suggest(oldSV *semver.SV) *semver.SV {
+	var next *semver.SV
+
+	switch r.Kind() {
+	case KindIncompatible:
+		if oldSV.MajorBig().Sign() == 0 {
+			next = oldSV.NextMinor()
+		} else {
+			next = oldSV.NextMajor()
+		}
+	case KindCompatible:
+		next = oldSV.NextMinor()
+	default:
+		next = oldSV.NextPatch()
+	}
+
+	next.ClearBuildIDs()
+
+	return next
+}
+
+// CheckTransition checks that the change of version from the old version
+// to the new is big enough for the changes in the Report. It returns an
+// error if the new version does not move forward from the old or if it is
+// not a big enough change. If the old version is a pre-release then there
+// is no promise of compatibility and any change is allowed in a version
+// with the same major, minor and patch numbers.
+func (r Report) CheckTransition(oldSV, newSV *semver.SV) error {
+	if semver.ComparePrecedence(oldSV, newSV) >= 0 {
+		return fmt.Errorf("the version %s does not move forward from %s",
+			newSV, oldSV)
+	}
+
+	k := r.Kind()
+	if k == KindNone {
+		return nil
+	}
+
+	if oldSV.HasPreRelIDs() && sameCore(oldSV, newSV) {
+		return nil
+	}
+
+	need := "minor"
+	if k == KindIncompatible && oldSV.MajorBig().Sign() != 0 {
+		need = "major"
+	}
+
+	if bigEnough(oldSV, newSV, need) {
+		return nil
+	}
+
+	return fmt.Errorf("the version %s is not a big enough change from %s"+
+		" - %s API changes need a new %s version: %s",
+		newSV, oldSV, k, need, strings.Join(r.descs(k), "; "))
+}
+
+// sameCore returns true if the two versions have the same major, minor and
+// patch version numbers
+func sameCore(a, b *semver.SV) bool {
+	return a.MajorBig().Cmp(b.MajorBig()) == 0 &&
+		a.MinorBig().Cmp(b.MinorBig()) == 0 &&
+		a.PatchBig().Cmp(b.PatchBig()) == 0
+}
+
+// bigEnough returns true if the new version has a greater major version
+// number than the old or, if only a new minor version is needed, the same
+// major version number and a greater minor version number
+func bigEnough(oldSV, newSV *semver.SV, need string) bool {
+	majorCmp := oldSV.MajorBig().Cmp(newSV.MajorBig())
+	if majorCmp < 0 {
+		return true
+	}
+
+	return need == "minor" && majorCmp == 0 &&
+		oldSV.MinorBig().Cmp(newSV.MinorBig()) < 0
+}
+
+// descs returns a description of each change of the given kind
+func (r Report) descs(k Kind) []string {
+	descs := []string{}
+
+	for _, c := range r.Changes {
+		if c.Kind == k {
+			descs = append(descs, c.Name+": "+c.Desc)
+		}
+	}
+
+	return descs
+}
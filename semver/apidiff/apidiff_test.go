@@ -0,0 +1,429 @@
+package apidiff_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nickwells/semver.mod/v3/semver"
+	"github.com/nickwells/semver.mod/v3/semver/apidiff"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+// writePkg writes the source of a package, with the package clause added,
+// into a new temporary directory and returns the directory name
+func writePkg(t *testing.T, src string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(dir, "p.go"),
+		[]byte("package p\n\n"+src), 0o600)
+	if err != nil {
+		t.Fatal("cannot write the package: ", err)
+	}
+
+	return dir
+}
+
+// changeStrs returns the changes in the Report as strings
+func changeStrs(r apidiff.Report) []string {
+	strs := []string{}
+	for _, c := range r.Changes {
+		strs = append(strs, c.String())
+	}
+
+	return strs
+}
+
+func TestCompare(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		oldSrc     string
+		newSrc     string
+		expChanges []string
+		expKind    apidiff.Kind
+	}{
+		{
+			ID: testhelper.MkID("no change"),
+			oldSrc: "func F(a int) error { return nil }\n" +
+				"func g() {}\n",
+			newSrc: "func F(a int) error { return g() }\n" +
+				"func g() error { return nil }\n" +
+				"var h = 1\n",
+			expChanges: []string{},
+			expKind:    apidiff.KindNone,
+		},
+		{
+			ID:     testhelper.MkID("func added"),
+			oldSrc: "func F() {}\n",
+			newSrc: "func F() {}\nfunc G() {}\n",
+			expChanges: []string{
+				"G: func added (compatible)",
+			},
+			expKind: apidiff.KindCompatible,
+		},
+		{
+			ID:     testhelper.MkID("func removed, var added"),
+			oldSrc: "func F() {}\n",
+			newSrc: "var V int\n",
+			expChanges: []string{
+				"F: func removed (incompatible)",
+				"V: var added (compatible)",
+			},
+			expKind: apidiff.KindIncompatible,
+		},
+		{
+			ID:     testhelper.MkID("func signature changed"),
+			oldSrc: "type T int\nfunc F(t T) {}\n",
+			newSrc: "type T int\nfunc F(t T, s string) {}\n",
+			expChanges: []string{
+				"F: type changed from func(T) to func(T, string)" +
+					" (incompatible)",
+			},
+			expKind: apidiff.KindIncompatible,
+		},
+		{
+			ID: testhelper.MkID("parameters renamed"),
+			oldSrc: "func F(a int) (s string) { return }\n" +
+				"func G[T any](t T, ts ...T) {}\n" +
+				"type T struct{ CB func(x int) }\n" +
+				"func (T) M(x int) {}\n" +
+				"type I interface{ N(y string) }\n" +
+				"var V []func(w int) error\n",
+			newSrc: "func F(renamed int) string { return \"\" }\n" +
+				"func G[T any](u T, us ...T) {}\n" +
+				"type T struct{ CB func(y int) }\n" +
+				"func (T) M(z int) {}\n" +
+				"type I interface{ N(other string) }\n" +
+				"var V []func(v int) (err error)\n",
+			expChanges: []string{},
+			expKind:    apidiff.KindNone,
+		},
+		{
+			ID:     testhelper.MkID("generic func type parameters changed"),
+			oldSrc: "func G[T any](t T) {}\n",
+			newSrc: "func G[T comparable](t T) {}\n",
+			expChanges: []string{
+				"G: type changed from func[T any](T)" +
+					" to func[T comparable](T) (incompatible)",
+			},
+			expKind: apidiff.KindIncompatible,
+		},
+		{
+			ID:     testhelper.MkID("func became a var"),
+			oldSrc: "func F() {}\n",
+			newSrc: "var F = func() {}\n",
+			expChanges: []string{
+				"F: changed from a func to a var (incompatible)",
+			},
+			expKind: apidiff.KindIncompatible,
+		},
+		{
+			ID:     testhelper.MkID("const value changed"),
+			oldSrc: "const C = 1\nconst D = \"x\"\n",
+			newSrc: "const C = 2\nconst D = \"x\"\n",
+			expChanges: []string{
+				"C: value changed from 1 to 2 (incompatible)",
+			},
+			expKind: apidiff.KindIncompatible,
+		},
+		{
+			ID: testhelper.MkID("struct fields"),
+			oldSrc: "import \"errors\"\n" +
+				"type S struct {\n" +
+				"\tA int\n\tB string\n\tc bool\n\tE error\n}\n" +
+				"var _ = errors.New\n",
+			newSrc: "type S struct {\n" +
+				"\tA int64\n\tC bool\n\td int\n\tE error\n}\n",
+			expChanges: []string{
+				"S.A: type changed from int to int64 (incompatible)",
+				"S.B: field removed (incompatible)",
+				"S.C: field added (compatible)",
+			},
+			expKind: apidiff.KindIncompatible,
+		},
+		{
+			ID: testhelper.MkID("methods"),
+			oldSrc: "type T struct{}\n" +
+				"func (T) M() {}\n" +
+				"func (*T) N(int) {}\n" +
+				"func (T) X() {}\n",
+			newSrc: "type T struct{}\n" +
+				"func (*T) M() {}\n" +
+				"func (*T) N(string) {}\n" +
+				"func (T) Y() {}\n" +
+				"func (T) z() {}\n",
+			expChanges: []string{
+				"T.N: type changed from func(int) to func(string)" +
+					" (incompatible)",
+				"T.X: method removed (incompatible)",
+				"T.Y: method added (compatible)",
+			},
+			expKind: apidiff.KindIncompatible,
+		},
+		{
+			ID:     testhelper.MkID("interface method added"),
+			oldSrc: "type I interface{ M() }\n",
+			newSrc: "type I interface{ M(); N() }\n",
+			expChanges: []string{
+				"I.N: method added (incompatible)",
+			},
+			expKind: apidiff.KindIncompatible,
+		},
+		{
+			ID:     testhelper.MkID("unexported interface method added"),
+			oldSrc: "type I interface{ M() }\n",
+			newSrc: "type I interface{ M(); sealed() }\n",
+			expChanges: []string{
+				"I.sealed: unexported method added (incompatible)",
+			},
+			expKind: apidiff.KindIncompatible,
+		},
+		{
+			ID:     testhelper.MkID("sealed interface method added"),
+			oldSrc: "type I interface{ M(); sealed() }\n",
+			newSrc: "type I interface{ M(); N(); sealed() }\n",
+			expChanges: []string{
+				"I.N: method added (compatible)",
+			},
+			expKind: apidiff.KindCompatible,
+		},
+		{
+			ID:     testhelper.MkID("underlying type changed"),
+			oldSrc: "type T int\ntype U []T\n",
+			newSrc: "type T struct{}\ntype U []T\n",
+			expChanges: []string{
+				"T: underlying type changed from int to struct{}" +
+					" (incompatible)",
+			},
+			expKind: apidiff.KindIncompatible,
+		},
+		{
+			ID:     testhelper.MkID("alias"),
+			oldSrc: "type A = int\ntype B = string\n",
+			newSrc: "type A = int64\ntype B string\n",
+			expChanges: []string{
+				"A: changed from an alias of int to an alias of int64" +
+					" (incompatible)",
+				"B: changed from an alias of string" +
+					" to a defined type with underlying type string" +
+					" (incompatible)",
+			},
+			expKind: apidiff.KindIncompatible,
+		},
+		{
+			ID:     testhelper.MkID("type parameters"),
+			oldSrc: "type L[T any] []T\n",
+			newSrc: "type L[T comparable] []T\n",
+			expChanges: []string{
+				"L: type parameters changed from [T any]" +
+					" to [T comparable] (incompatible)",
+			},
+			expKind: apidiff.KindIncompatible,
+		},
+	}
+
+	for _, tc := range testCases {
+		r, err := apidiff.Compare(writePkg(t, tc.oldSrc),
+			writePkg(t, tc.newSrc))
+		if err != nil {
+			t.Fatal(tc.IDStr(), ": unexpected error: ", err)
+		}
+
+		testhelper.DiffStringSlice(t, tc.IDStr(), "changes",
+			changeStrs(r), tc.expChanges)
+		testhelper.DiffString(t, tc.IDStr(), "kind",
+			r.Kind().String(), tc.expKind.String())
+	}
+}
+
+func TestCompareErr(t *testing.T) {
+	good := writePkg(t, "func F() {}\n")
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		oldDir string
+		newDir string
+	}{
+		{
+			ID:     testhelper.MkID("no package"),
+			ExpErr: testhelper.MkExpErr("cannot find the Go package in"),
+			oldDir: t.TempDir(),
+			newDir: good,
+		},
+		{
+			ID:     testhelper.MkID("syntax error"),
+			ExpErr: testhelper.MkExpErr("cannot parse the Go package in"),
+			oldDir: good,
+			newDir: writePkg(t, "func F( {}\n"),
+		},
+		{
+			ID:     testhelper.MkID("type error"),
+			ExpErr: testhelper.MkExpErr("cannot type-check the Go package"),
+			oldDir: good,
+			newDir: writePkg(t, "func F() int { return \"x\" }\n"),
+		},
+	}
+
+	for _, tc := range testCases {
+		_, err := apidiff.Compare(tc.oldDir, tc.newDir)
+		testhelper.CheckExpErr(t, err, tc)
+	}
+}
+
+func TestCheckTransition(t *testing.T) {
+	reports := map[apidiff.Kind]apidiff.Report{
+		apidiff.KindNone: {},
+		apidiff.KindCompatible: {Changes: []apidiff.Change{
+			{Name: "G", Kind: apidiff.KindCompatible, Desc: "func added"},
+		}},
+		apidiff.KindIncompatible: {Changes: []apidiff.Change{
+			{Name: "G", Kind: apidiff.KindCompatible, Desc: "func added"},
+			{Name: "F", Kind: apidiff.KindIncompatible, Desc: "func removed"},
+		}},
+	}
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		kind       apidiff.Kind
+		oldSV      string
+		newSV      string
+		expSuggest string
+	}{
+		{
+			ID:         testhelper.MkID("none, patch"),
+			kind:       apidiff.KindNone,
+			oldSV:      "v3.1.0",
+			newSV:      "v3.1.1",
+			expSuggest: "v3.1.1",
+		},
+		{
+			ID:     testhelper.MkID("none, backwards"),
+			kind:   apidiff.KindNone,
+			oldSV:  "v3.1.0",
+			newSV:  "v3.0.9",
+			ExpErr: testhelper.MkExpErr("v3.0.9 does not move forward"),
+		},
+		{
+			ID:    testhelper.MkID("compatible, patch"),
+			kind:  apidiff.KindCompatible,
+			oldSV: "v3.1.0",
+			newSV: "v3.1.1",
+			ExpErr: testhelper.MkExpErr(
+				"the version v3.1.1 is not a big enough change from v3.1.0",
+				"compatible API changes need a new minor version",
+				"G: func added"),
+			expSuggest: "v3.2.0",
+		},
+		{
+			ID:         testhelper.MkID("compatible, minor"),
+			kind:       apidiff.KindCompatible,
+			oldSV:      "v3.1.0",
+			newSV:      "v3.2.0",
+			expSuggest: "v3.2.0",
+		},
+		{
+			ID:         testhelper.MkID("compatible, major"),
+			kind:       apidiff.KindCompatible,
+			oldSV:      "v3.1.0",
+			newSV:      "v4.0.0",
+			expSuggest: "v3.2.0",
+		},
+		{
+			ID:    testhelper.MkID("incompatible, minor"),
+			kind:  apidiff.KindIncompatible,
+			oldSV: "v3.1.0",
+			newSV: "v3.2.0",
+			ExpErr: testhelper.MkExpErr(
+				"incompatible API changes need a new major version",
+				"F: func removed"),
+			expSuggest: "v4.0.0",
+		},
+		{
+			ID:         testhelper.MkID("incompatible, major pre-release"),
+			kind:       apidiff.KindIncompatible,
+			oldSV:      "v3.1.0",
+			newSV:      "v4.0.0-rc.1",
+			expSuggest: "v4.0.0",
+		},
+		{
+			ID:         testhelper.MkID("incompatible, initial development"),
+			kind:       apidiff.KindIncompatible,
+			oldSV:      "v0.3.2",
+			newSV:      "v0.4.0",
+			expSuggest: "v0.4.0",
+		},
+		{
+			ID:    testhelper.MkID("incompatible, initial development, patch"),
+			kind:  apidiff.KindIncompatible,
+			oldSV: "v0.3.2",
+			newSV: "v0.3.3",
+			ExpErr: testhelper.MkExpErr(
+				"incompatible API changes need a new minor version"),
+			expSuggest: "v0.4.0",
+		},
+		{
+			ID:         testhelper.MkID("incompatible, from pre-release"),
+			kind:       apidiff.KindIncompatible,
+			oldSV:      "v4.0.0-rc.1",
+			newSV:      "v4.0.0",
+			expSuggest: "v5.0.0",
+		},
+		{
+			ID:    testhelper.MkID("incompatible, from pre-release, patch"),
+			kind:  apidiff.KindIncompatible,
+			oldSV: "v4.0.0-rc.1",
+			newSV: "v4.0.1",
+			ExpErr: testhelper.MkExpErr(
+				"incompatible API changes need a new major version"),
+			expSuggest: "v5.0.0",
+		},
+	}
+
+	for _, tc := range testCases {
+		oldSV, err := semver.ParseSV(tc.oldSV)
+		if err != nil {
+			t.Fatal(tc.IDStr(), ": unexpected error: ", err)
+		}
+
+		newSV, err := semver.ParseSV(tc.newSV)
+		if err != nil {
+			t.Fatal(tc.IDStr(), ": unexpected error: ", err)
+		}
+
+		r := reports[tc.kind]
+
+		err = r.CheckTransition(oldSV, newSV)
+		testhelper.CheckExpErr(t, err, tc)
+
+		if tc.expSuggest != "" {
+			testhelper.DiffString(t, tc.IDStr(), "suggestion",
+				r.Suggest(oldSV).String(), tc.expSuggest)
+		}
+	}
+}
+
+func TestFuncAddedNeedsMinor(t *testing.T) {
+	tc := struct {
+		testhelper.ID
+		testhelper.ExpErr
+	}{
+		ID: testhelper.MkID("func added, patch release"),
+		ExpErr: testhelper.MkExpErr(
+			"v3.1.1 is not a big enough change from v3.1.0",
+			"G: func added"),
+	}
+
+	r, err := apidiff.Compare(writePkg(t, "func F() {}\n"),
+		writePkg(t, "func F() {}\nfunc G() {}\n"))
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+
+	err = r.CheckTransition(semver.NewSVOrPanic(3, 1, 0, nil, nil),
+		semver.NewSVOrPanic(3, 1, 1, nil, nil))
+	testhelper.CheckExpErr(t, err, tc)
+}
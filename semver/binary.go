@@ -0,0 +1,334 @@
+package semver
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+)
+
+// These constants are used in building the binary sort key
+const (
+	binKeyEnd       byte = 0x00
+	binKeyNumericID byte = 0x01
+	binKeyAlphaID   byte = 0x02
+	binKeyRelease   byte = 0x03
+	binKeyBuildID   byte = 0x01
+	binKeyLongNum   byte = 0xff
+	binKeyUintLen        = 8
+)
+
+// appendBinKeyNum appends the order-preserving encoding of the number to
+// dst. This is a single byte giving the number of bytes needed to hold the
+// number followed by those bytes, most significant first. Zero is encoded
+// as a single zero byte. A number needing 255 or more bytes has a 0xff
+// byte followed by the number of bytes as an 8-byte big-endian value. A
+// number with more bytes is always larger so the encodings compare in the
+// same order as the numbers.
+func appendBinKeyNum(dst []byte, n vNum) []byte {
+	var nb []byte
+
+	if u, err := strconv.ParseUint(n.String(), 10, 64); err == nil {
+		nb = binary.BigEndian.AppendUint64(nil, u)
+		nb = bytes.TrimLeft(nb, "\x00")
+	} else {
+		nb = n.toBig().Bytes()
+	}
+
+	if len(nb) < int(binKeyLongNum) {
+		dst = append(dst, byte(len(nb)))
+	} else {
+		dst = append(dst, binKeyLongNum)
+		dst = binary.BigEndian.AppendUint64(dst, uint64(len(nb)))
+	}
+
+	return append(dst, nb...)
+}
+
+// AppendSortKey appends a compact binary encoding of the SV to dst and
+// returns the extended slice. The encodings of two SVs compare (using
+// bytes.Compare) in the same order as the SVs do by Compare. The encoding
+// is:
+//
+//   - the major, minor and patch numbers, each written as a byte giving the
+//     length of the number in bytes followed by the number, most
+//     significant byte first. A number of 255 bytes or more has its length
+//     written as a 0xff byte followed by the length as 8 bytes
+//   - for a release version a single 0x03 byte, otherwise each of the
+//     pre-release IDs followed by a 0x00 byte. A numeric ID is written as a
+//     0x01 byte followed by the number, encoded as above, and an
+//     alphanumeric ID as a 0x02 byte followed by the ID and a 0x00 byte
+//
+// The build IDs play no part in the precedence of the SV and so are not
+// included (but see MarshalBinary). The key is self-delimiting and so it can
+// be followed by other data; DecodeSortKey reports the length of the key.
+func (sv SV) AppendSortKey(dst []byte) []byte {
+	dst = appendBinKeyNum(dst, sv.major)
+	dst = appendBinKeyNum(dst, sv.minor)
+	dst = appendBinKeyNum(dst, sv.patch)
+
+	if len(sv.preRelIDs) == 0 {
+		return append(dst, binKeyRelease)
+	}
+
+	for _, id := range sv.preRelIDs {
+		if numericOnlyRE.MatchString(id) {
+			dst = append(dst, binKeyNumericID)
+			dst = appendBinKeyNum(dst, vNum(id))
+		} else {
+			dst = append(dst, binKeyAlphaID)
+			dst = append(dst, id...)
+			dst = append(dst, binKeyEnd)
+		}
+	}
+
+	return append(dst, binKeyEnd)
+}
+
+// cutBinKeyNum decodes the leading number from the binary sort key and
+// returns it and the rest of the key
+func cutBinKeyNum(b []byte, name string) (vNum, []byte, error) {
+	if len(b) == 0 {
+		return "", nil, fmt.Errorf("the %s number is missing", name)
+	}
+
+	l := uint64(b[0])
+	b = b[1:]
+
+	if l == uint64(binKeyLongNum) {
+		if len(b) < binKeyUintLen {
+			return "", nil,
+				fmt.Errorf("the %s number length is truncated", name)
+		}
+
+		l = binary.BigEndian.Uint64(b)
+		b = b[binKeyUintLen:]
+
+		if l < uint64(binKeyLongNum) {
+			return "", nil, fmt.Errorf(
+				"the %s number length (%d) should not use the long form",
+				name, l)
+		}
+	}
+
+	if uint64(len(b)) < l {
+		return "", nil, fmt.Errorf("the %s number is truncated", name)
+	}
+
+	if l > 0 && b[0] == 0 {
+		return "", nil, fmt.Errorf("the %s number has a leading zero byte",
+			name)
+	}
+
+	nb, rest := b[:l], b[l:]
+
+	if l <= binKeyUintLen {
+		var u uint64
+		for _, c := range nb {
+			u = u<<8 | uint64(c)
+		}
+
+		return vNum(strconv.FormatUint(u, 10)), rest, nil
+	}
+
+	return vNum(new(big.Int).SetBytes(nb).String()), rest, nil
+}
+
+// cutBinKeyString decodes the leading zero-terminated string from the
+// binary sort key and returns it and the rest of the key
+func cutBinKeyString(b []byte, name string) (string, []byte, error) {
+	s, rest, ok := bytes.Cut(b, []byte{binKeyEnd})
+	if !ok {
+		return "", nil, fmt.Errorf("the %s is not terminated", name)
+	}
+
+	return string(s), rest, nil
+}
+
+// DecodeSortKey decodes the binary sort key at the start of b (see
+// AppendSortKey) and returns the SV and the number of bytes of b that the
+// key uses. The SV will have no build IDs. It returns an error if the key is
+// not well-formed.
+func DecodeSortKey(b []byte) (*SV, int, error) {
+	sv, rest, err := decodeSortKey(b)
+	if err != nil {
+		return nil, 0, fmt.Errorf("bad %s binary sort key - %w", Name, err)
+	}
+
+	return sv, len(b) - len(rest), nil
+}
+
+// decodeSortKey decodes the binary sort key at the start of b and returns
+// the SV and the remaining bytes
+//
+//nolint:cyclop
+func decodeSortKey(b []byte) (*SV, []byte, error) {
+	var (
+		major, minor, patch vNum
+		err                 error
+	)
+
+	if major, b, err = cutBinKeyNum(b, "major"); err != nil {
+		return nil, nil, err
+	}
+
+	if minor, b, err = cutBinKeyNum(b, "minor"); err != nil {
+		return nil, nil, err
+	}
+
+	if patch, b, err = cutBinKeyNum(b, "patch"); err != nil {
+		return nil, nil, err
+	}
+
+	if len(b) == 0 {
+		return nil, nil,
+			errors.New("the pre-release IDs or release marker are missing")
+	}
+
+	if b[0] == binKeyRelease {
+		sv, err := newSV(major, minor, patch, nil, nil)
+
+		return sv, b[1:], err
+	}
+
+	prIDs := []string{}
+
+	for {
+		if len(b) == 0 {
+			return nil, nil,
+				errors.New("the pre-release IDs are not terminated")
+		}
+
+		tag := b[0]
+		b = b[1:]
+
+		var id string
+
+		switch tag {
+		case binKeyEnd:
+			if len(prIDs) == 0 {
+				return nil, nil, errors.New("there are no pre-release IDs")
+			}
+
+			sv, err := newSV(major, minor, patch, prIDs, nil)
+
+			return sv, b, err
+		case binKeyNumericID:
+			var n vNum
+
+			n, b, err = cutBinKeyNum(b, "numeric pre-release ID")
+			if err != nil {
+				return nil, nil, err
+			}
+
+			id = string(n)
+		case binKeyAlphaID:
+			id, b, err = cutBinKeyString(b, "pre-release ID")
+			if err != nil {
+				return nil, nil, err
+			}
+
+			if numericOnlyRE.MatchString(id) {
+				return nil, nil, fmt.Errorf(
+					"the pre-release ID %q is numeric but is not marked so",
+					id)
+			}
+		default:
+			return nil, nil,
+				fmt.Errorf("a pre-release ID has a bad type (%#02x)", tag)
+		}
+
+		prIDs = append(prIDs, id)
+	}
+}
+
+// MarshalBinary returns a binary encoding of the SV. This is the sort key
+// (see AppendSortKey) followed by the build IDs, each written as a 0x01 byte
+// followed by the ID and a 0x00 byte, and finally a 0x00 byte. The
+// encodings of two SVs compare (using bytes.Compare) in the same order as
+// the SVs do by CompareWithBuild. An SV which has not been set is encoded
+// as an empty slice. This satisfies the encoding.BinaryMarshaler interface.
+func (sv SV) MarshalBinary() ([]byte, error) {
+	if !sv.hasBeenSet {
+		return []byte{}, nil
+	}
+
+	b := sv.AppendSortKey(nil)
+
+	for _, id := range sv.buildIDs {
+		b = append(b, binKeyBuildID)
+		b = append(b, id...)
+		b = append(b, binKeyEnd)
+	}
+
+	return append(b, binKeyEnd), nil
+}
+
+// UnmarshalBinary sets the SV from the binary encoding (see
+// MarshalBinary). If the data is empty the SV is cleared and will not be
+// marked as having been set. This satisfies the encoding.BinaryUnmarshaler
+// interface.
+func (sv *SV) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		*sv = SV{}
+
+		return nil
+	}
+
+	newSV, err := unmarshalBinary(data)
+	if err != nil {
+		return fmt.Errorf("bad %s binary encoding - %w", Name, err)
+	}
+
+	*sv = *newSV
+
+	return nil
+}
+
+// unmarshalBinary decodes the binary encoding of an SV, including its
+// build IDs
+func unmarshalBinary(data []byte) (*SV, error) {
+	sv, b, err := decodeSortKey(data)
+	if err != nil {
+		return nil, err
+	}
+
+	buildIDs := []string{}
+
+	for {
+		if len(b) == 0 {
+			return nil, errors.New("the build IDs are not terminated")
+		}
+
+		tag := b[0]
+		b = b[1:]
+
+		if tag == binKeyEnd {
+			break
+		}
+
+		if tag != binKeyBuildID {
+			return nil, fmt.Errorf("a build ID has a bad type (%#02x)", tag)
+		}
+
+		var id string
+
+		if id, b, err = cutBinKeyString(b, "build ID"); err != nil {
+			return nil, err
+		}
+
+		buildIDs = append(buildIDs, id)
+	}
+
+	if len(b) != 0 {
+		return nil, fmt.Errorf("there are %d bytes of trailing data", len(b))
+	}
+
+	if err := sv.SetBuildIDs(buildIDs); err != nil {
+		return nil, err
+	}
+
+	return sv, nil
+}
@@ -0,0 +1,288 @@
+package semver_test
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/nickwells/semver.mod/v3/semver"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+// sortKeyTestSVs is a list of versions used to check the ordering of the
+// binary encodings
+var sortKeyTestSVs = []string{
+	"v0.0.0-0",
+	"v0.0.0",
+	"v0.0.1",
+	"v0.0.255",
+	"v0.0.256",
+	"v0.1.0",
+	"v1.0.0-0",
+	"v1.0.0-1",
+	"v1.0.0-255",
+	"v1.0.0-256",
+	"v1.0.0-256.0",
+	"v1.0.0-A",
+	"v1.0.0-alpha",
+	"v1.0.0-alpha.1",
+	"v1.0.0-alpha.beta",
+	"v1.0.0-alpha0",
+	"v1.0.0-beta.2",
+	"v1.0.0-beta.11",
+	"v1.0.0-rc.1",
+	"v1.0.0-rc.1+b",
+	"v1.0.0",
+	"v1.0.0+a",
+	"v1.0.0+a.b",
+	"v1.0.0+ab",
+	"v1.0.0+b",
+	"v1.2.3",
+	"v1.10.0",
+	"v256.0.0",
+	"v65536.0.0",
+	"v18446744073709551615.0.0-1",
+	"v18446744073709551615.0.0-18446744073709551616",
+	"v18446744073709551615.0.0",
+	"v18446744073709551616.0.0",
+	"v99999999999999999999999999.0.0",
+	"v" + strings.Repeat("9", 700) + ".0.0",
+	"v1" + strings.Repeat("0", 700) + ".0.0",
+}
+
+func TestAppendSortKey(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		svStr  string
+		expKey []byte
+	}{
+		{
+			ID:     testhelper.MkID("v0.0.0"),
+			svStr:  "v0.0.0",
+			expKey: []byte{0, 0, 0, 0x03},
+		},
+		{
+			ID:     testhelper.MkID("v1.256.3"),
+			svStr:  "v1.256.3+build",
+			expKey: []byte{1, 1, 2, 1, 0, 1, 3, 0x03},
+		},
+		{
+			ID:    testhelper.MkID("v1.2.3-rc.0.12"),
+			svStr: "v1.2.3-rc.0.12",
+			expKey: []byte{
+				1, 1, 1, 2, 1, 3,
+				0x02, 'r', 'c', 0x00,
+				0x01, 0,
+				0x01, 1, 12,
+				0x00,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		sv, err := semver.ParseSV(tc.svStr)
+		if err != nil {
+			t.Fatal("Couldn't parse the semver: ", err)
+		}
+
+		pfx := []byte("pfx")
+		key := sv.AppendSortKey(pfx)
+
+		if !bytes.Equal(key[len(pfx):], tc.expKey) {
+			t.Log(tc.IDStr())
+			t.Logf("\t: expected: %v", tc.expKey)
+			t.Logf("\t:      got: %v", key[len(pfx):])
+			t.Errorf("\t: bad sort key")
+		}
+
+		key = append(key, "trailing data"...)
+
+		keySV, n, err := semver.DecodeSortKey(key[len(pfx):])
+		if err != nil {
+			t.Log(tc.IDStr())
+			t.Errorf("\t: unexpected error from DecodeSortKey: %s", err)
+
+			continue
+		}
+
+		testhelper.DiffInt(t, tc.IDStr(), "key length", n, len(tc.expKey))
+
+		expSV := semver.NewSVOrPanic(sv.Major(), sv.Minor(), sv.Patch(),
+			sv.PreRelIDs(), nil)
+		testhelper.DiffString(t, tc.IDStr(), "decoded SV",
+			keySV.String(), expSV.String())
+	}
+}
+
+// sign returns -1, 0 or 1 according to the sign of i
+func sign(i int) int {
+	return cmp.Compare(i, 0)
+}
+
+func TestSortKeyOrder(t *testing.T) {
+	svs := make([]*semver.SV, 0, len(sortKeyTestSVs))
+
+	for _, s := range sortKeyTestSVs {
+		sv, err := semver.ParseSV(s)
+		if err != nil {
+			t.Fatal("Couldn't parse the semver: ", err)
+		}
+
+		svs = append(svs, sv)
+	}
+
+	for _, a := range svs {
+		aKey := a.AppendSortKey(nil)
+
+		aBin, err := a.MarshalBinary()
+		if err != nil {
+			t.Fatal("Couldn't marshal the semver: ", err)
+		}
+
+		for _, b := range svs {
+			id := fmt.Sprintf("%s vs %s", a, b)
+
+			bKey := b.AppendSortKey(nil)
+			testhelper.DiffInt(t, id, "sort key comparison",
+				bytes.Compare(aKey, bKey), sign(semver.Compare(a, b)))
+
+			bBin, err := b.MarshalBinary()
+			if err != nil {
+				t.Fatal("Couldn't marshal the semver: ", err)
+			}
+
+			testhelper.DiffInt(t, id, "binary encoding comparison",
+				bytes.Compare(aBin, bBin), sign(semver.CompareWithBuild(a, b)))
+		}
+	}
+}
+
+func TestBinaryRoundTrip(t *testing.T) {
+	for _, s := range append(sortKeyTestSVs, "") {
+		var sv semver.SV
+		if s != "" {
+			sv = *semver.NewSVOrPanic(0, 0, 0, nil, nil)
+			if err := sv.UnmarshalText([]byte(s)); err != nil {
+				t.Fatal("Couldn't parse the semver: ", err)
+			}
+		}
+
+		data, err := sv.MarshalBinary()
+		if err != nil {
+			t.Fatal("Couldn't marshal the semver: ", err)
+		}
+
+		got := semver.NewSVOrPanic(9, 9, 9, nil, nil)
+		if err := got.UnmarshalBinary(data); err != nil {
+			t.Log(s)
+			t.Errorf("\t: unexpected error from UnmarshalBinary: %s", err)
+
+			continue
+		}
+
+		testhelper.DiffString(t, s, "round trip", got.String(), s)
+		testhelper.DiffBool(t, s, "has been set", got.HasBeenSet(), s != "")
+	}
+}
+
+func TestUnmarshalBinaryErrs(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		data []byte
+	}{
+		{
+			ID:     testhelper.MkID("missing minor"),
+			data:   []byte{1, 1},
+			ExpErr: testhelper.MkExpErr("the minor number is missing"),
+		},
+		{
+			ID:     testhelper.MkID("truncated number"),
+			data:   []byte{2, 1},
+			ExpErr: testhelper.MkExpErr("the major number is truncated"),
+		},
+		{
+			ID:   testhelper.MkID("long number length truncated"),
+			data: []byte{0xff, 0, 0, 0},
+			ExpErr: testhelper.MkExpErr(
+				"the major number length is truncated"),
+		},
+		{
+			ID:   testhelper.MkID("long form for a short number"),
+			data: []byte{0xff, 0, 0, 0, 0, 0, 0, 0, 1, 1},
+			ExpErr: testhelper.MkExpErr(
+				"the major number length (1) should not use the long form"),
+		},
+		{
+			ID:   testhelper.MkID("leading zero byte"),
+			data: []byte{2, 0, 1},
+			ExpErr: testhelper.MkExpErr(
+				"the major number has a leading zero byte"),
+		},
+		{
+			ID:   testhelper.MkID("no release marker"),
+			data: []byte{0, 0, 0},
+			ExpErr: testhelper.MkExpErr(
+				"the pre-release IDs or release marker are missing"),
+		},
+		{
+			ID:     testhelper.MkID("no pre-release IDs"),
+			data:   []byte{0, 0, 0, 0x00},
+			ExpErr: testhelper.MkExpErr("there are no pre-release IDs"),
+		},
+		{
+			ID:   testhelper.MkID("unterminated pre-release IDs"),
+			data: []byte{0, 0, 0, 0x01, 0},
+			ExpErr: testhelper.MkExpErr(
+				"the pre-release IDs are not terminated"),
+		},
+		{
+			ID:   testhelper.MkID("bad pre-release ID type"),
+			data: []byte{0, 0, 0, 0x04},
+			ExpErr: testhelper.MkExpErr(
+				"a pre-release ID has a bad type (0x04)"),
+		},
+		{
+			ID:   testhelper.MkID("numeric ID marked as alphanumeric"),
+			data: []byte{0, 0, 0, 0x02, '1', 0x00, 0x00, 0x00},
+			ExpErr: testhelper.MkExpErr(
+				`the pre-release ID "1" is numeric but is not marked so`),
+		},
+		{
+			ID:   testhelper.MkID("bad pre-release ID"),
+			data: []byte{0, 0, 0, 0x02, '_', 0x00, 0x00, 0x00},
+			ExpErr: testhelper.MkExpErr(
+				"the Pre-Rel ID: '_' must be " + semver.GoodIDDesc),
+		},
+		{
+			ID:     testhelper.MkID("unterminated build IDs"),
+			data:   []byte{0, 0, 0, 0x03},
+			ExpErr: testhelper.MkExpErr("the build IDs are not terminated"),
+		},
+		{
+			ID:     testhelper.MkID("unterminated build ID"),
+			data:   []byte{0, 0, 0, 0x03, 0x01, 'a'},
+			ExpErr: testhelper.MkExpErr("the build ID is not terminated"),
+		},
+		{
+			ID:     testhelper.MkID("bad build ID type"),
+			data:   []byte{0, 0, 0, 0x03, 0x02, 'a', 0x00, 0x00},
+			ExpErr: testhelper.MkExpErr("a build ID has a bad type (0x02)"),
+		},
+		{
+			ID:   testhelper.MkID("trailing data"),
+			data: []byte{0, 0, 0, 0x03, 0x00, 0x00},
+			ExpErr: testhelper.MkExpErr(
+				"there are 1 bytes of trailing data"),
+		},
+	}
+
+	for _, tc := range testCases {
+		var sv semver.SV
+
+		err := sv.UnmarshalBinary(tc.data)
+		testhelper.CheckExpErr(t, err, tc)
+	}
+}
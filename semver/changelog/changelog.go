@@ -0,0 +1,327 @@
+/*
+Package changelog writes a CHANGELOG.md file in the Keep a Changelog style
+(see https://keepachangelog.com) from Conventional Commits. The commits of
+each release are grouped under a heading giving the version, such as:
+
+	## [1.2.0] - 2026-10-16
+
+and, within that, into sections for breaking changes, features and fixes.
+Commits of any other type do not appear.
+
+The releases are written in descending order of version. An existing
+changelog can be merged with new releases; its version headings are parsed
+using semver.ParseStrictSV and the text of each existing release is kept
+unchanged.
+*/
+package changelog
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/nickwells/semver.mod/v3/semver"
+	"github.com/nickwells/semver.mod/v3/semver/convcommit"
+)
+
+// DefaultPreamble is written at the start of a new changelog
+const DefaultPreamble = "# Changelog\n" +
+	"\n" +
+	"All notable changes to this project will be documented in this file.\n" +
+	"\n" +
+	"The format is based on" +
+	" [Keep a Changelog](https://keepachangelog.com/en/1.1.0/),\n" +
+	"and this project adheres to" +
+	" [Semantic Versioning](https://semver.org/spec/v2.0.0.html).\n"
+
+// These are the titles of the sections within a release
+const (
+	SectionBreaking = "Breaking Changes"
+	SectionFeatures = "Features"
+	SectionFixes    = "Fixes"
+)
+
+// dateFormat is the format of the date in a release heading
+const dateFormat = time.DateOnly
+
+// releaseHeadingPfx starts the heading of each release
+const releaseHeadingPfx = "## "
+
+var (
+	// headingVsnRE matches the version in a release heading, with or
+	// without the surrounding brackets
+	headingVsnRE = regexp.MustCompile(`^## \[?([^\]\s]+)\]?(?:\s|$)`)
+	// linkRefRE matches a link reference definition
+	linkRefRE = regexp.MustCompile(`^\[[^\]]+\]:\s`)
+)
+
+// Release holds the commits of one version
+type Release struct {
+	// SV is the version of the release
+	SV *semver.SV
+	// Date is the date of the release; if it is the zero time it is not
+	// shown
+	Date time.Time
+	// Commits are the commits in the release
+	Commits []*convcommit.Commit
+}
+
+// heading returns the heading of the release
+func (r Release) heading() string {
+	h := releaseHeadingPfx + "[" + strings.TrimPrefix(r.SV.String(), "v") + "]"
+	if !r.Date.IsZero() {
+		h += " - " + r.Date.Format(dateFormat)
+	}
+
+	return h
+}
+
+// sectionOf returns the title of the section for the commit. The boolean
+// return value is false if the commit does not appear in the changelog.
+func sectionOf(c *convcommit.Commit) (string, bool) {
+	switch {
+	case c.Breaking:
+		return SectionBreaking, true
+	case c.Type == convcommit.TypeFeat:
+		return SectionFeatures, true
+	case c.Type == convcommit.TypeFix:
+		return SectionFixes, true
+	}
+
+	return "", false
+}
+
+// entry returns the changelog entry for the commit. For a breaking change
+// the text of any BREAKING CHANGE footer is used in place of the
+// description.
+func entry(c *convcommit.Commit) string {
+	text := c.Description
+
+	if c.Breaking {
+		for _, f := range c.Footers {
+			if f.Token == convcommit.BreakingChange ||
+				f.Token == convcommit.BreakingChangeHyphen {
+				text = strings.Join(strings.Fields(f.Value), " ")
+
+				break
+			}
+		}
+	}
+
+	if c.Scope != "" {
+		return "- **" + c.Scope + ":** " + text
+	}
+
+	return "- " + text
+}
+
+// text returns the text of the release: the heading followed by a section
+// for each kind of change having any commits
+func (r Release) text() string {
+	entries := map[string][]string{}
+
+	for _, c := range r.Commits {
+		if s, ok := sectionOf(c); ok {
+			entries[s] = append(entries[s], entry(c))
+		}
+	}
+
+	var b strings.Builder
+
+	b.WriteString(r.heading())
+	b.WriteString("\n")
+
+	for _, s := range []string{SectionBreaking, SectionFeatures, SectionFixes} {
+		if len(entries[s]) == 0 {
+			continue
+		}
+
+		b.WriteString("\n### " + s + "\n\n")
+		b.WriteString(strings.Join(entries[s], "\n"))
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// section is a release in the changelog. The text starts with the heading.
+type section struct {
+	sv   *semver.SV
+	text string
+}
+
+// changelog is a parsed changelog file
+type changelog struct {
+	preamble string
+	sections []section
+	linkRefs string
+}
+
+// headingSV returns the version in the line if it is a release heading.
+// The boolean return value is false otherwise.
+func headingSV(line string) (*semver.SV, bool) {
+	parts := headingVsnRE.FindStringSubmatch(line)
+	if parts == nil {
+		return nil, false
+	}
+
+	sv, err := semver.ParseStrictSV(parts[1])
+	if err != nil {
+		return nil, false
+	}
+
+	return sv, true
+}
+
+// parse reads the existing changelog. Everything before the first release
+// heading is the preamble; this will include any "Unreleased" section. A
+// block of link reference definitions at the end is kept apart so that it
+// stays at the end when releases are added. Any other heading of the same
+// level is taken to be part of the release before it.
+func parse(r io.Reader) (changelog, error) {
+	var (
+		cl    changelog
+		lines []string
+	)
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+
+	if err := scanner.Err(); err != nil {
+		return cl, fmt.Errorf("cannot read the changelog: %w", err)
+	}
+
+	end := len(lines)
+	for end > 0 && strings.TrimSpace(lines[end-1]) == "" {
+		end--
+	}
+
+	refStart := end
+	for refStart > 0 && linkRefRE.MatchString(lines[refStart-1]) {
+		refStart--
+	}
+
+	cl.linkRefs = strings.Join(lines[refStart:end], "\n")
+	lines = lines[:refStart]
+
+	var cur *section
+
+	start := 0
+
+	for i, line := range lines {
+		sv, ok := headingSV(line)
+		if !ok {
+			continue
+		}
+
+		if cur == nil {
+			cl.preamble = strings.Join(lines[start:i], "\n")
+		} else {
+			cur.text = strings.Join(lines[start:i], "\n")
+			cl.sections = append(cl.sections, *cur)
+		}
+
+		cur = &section{sv: sv}
+		start = i
+	}
+
+	if cur == nil {
+		cl.preamble = strings.Join(lines, "\n")
+	} else {
+		cur.text = strings.Join(lines[start:], "\n")
+		cl.sections = append(cl.sections, *cur)
+	}
+
+	return cl, nil
+}
+
+// add adds the releases to the changelog. A release whose version is
+// already in the changelog is not added.
+func (cl *changelog) add(releases []Release) {
+	for _, r := range releases {
+		if slices.ContainsFunc(cl.sections, func(s section) bool {
+			return semver.Equals(s.sv, r.SV)
+		}) {
+			continue
+		}
+
+		cl.sections = append(cl.sections, section{sv: r.SV, text: r.text()})
+	}
+
+	slices.SortStableFunc(cl.sections, func(a, b section) int {
+		switch {
+		case semver.Less(b.sv, a.sv):
+			return -1
+		case semver.Less(a.sv, b.sv):
+			return 1
+		}
+
+		return 0
+	})
+}
+
+// write writes the changelog. The parts are separated by a single blank
+// line.
+func (cl changelog) write(w io.Writer) error {
+	parts := []string{cl.preamble}
+	for _, s := range cl.sections {
+		parts = append(parts, s.text)
+	}
+
+	parts = append(parts, cl.linkRefs)
+
+	var b strings.Builder
+
+	for _, p := range parts {
+		p = strings.TrimRight(p, " \t\n")
+		if p == "" {
+			continue
+		}
+
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+
+		b.WriteString(p)
+		b.WriteString("\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+// Write writes a new changelog with the releases, starting with the
+// DefaultPreamble
+func Write(w io.Writer, releases []Release) error {
+	cl := changelog{preamble: DefaultPreamble}
+	cl.add(releases)
+
+	return cl.write(w)
+}
+
+// Merge reads the existing changelog, adds the releases to it and writes
+// the result. The releases, old and new, are written in descending order
+// of version (according to semver.Less). A release whose version already
+// has a heading in the existing changelog is not added; the existing text
+// is kept. If the existing changelog is empty the DefaultPreamble is used.
+func Merge(w io.Writer, existing io.Reader, releases []Release) error {
+	cl, err := parse(existing)
+	if err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(cl.preamble) == "" && len(cl.sections) == 0 {
+		cl.preamble = DefaultPreamble
+	}
+
+	cl.add(releases)
+
+	return cl.write(w)
+}
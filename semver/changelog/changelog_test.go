@@ -0,0 +1,169 @@
+package changelog_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/nickwells/semver.mod/v3/semver"
+	"github.com/nickwells/semver.mod/v3/semver/changelog"
+	"github.com/nickwells/semver.mod/v3/semver/convcommit"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+var gfc = testhelper.GoldenFileCfg{
+	DirNames:    []string{"testdata"},
+	Sfx:         "md",
+	UpdFlagName: "upd-gf",
+}
+
+func init() {
+	gfc.AddUpdateFlag()
+}
+
+// mkRelease returns a Release with the version, date and commits made from
+// the messages. A date of "" gives the zero time.
+func mkRelease(t *testing.T, vsn, date string, msgs ...string,
+) changelog.Release {
+	t.Helper()
+
+	sv, err := semver.ParseSV(vsn)
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+
+	r := changelog.Release{SV: sv}
+
+	if date != "" {
+		d, err := time.Parse(time.DateOnly, date)
+		if err != nil {
+			t.Fatal("bad date: ", err)
+		}
+
+		r.Date = d
+	}
+
+	for _, msg := range msgs {
+		c, err := convcommit.Parse(msg)
+		if err != nil {
+			t.Fatal("unexpected error: ", err)
+		}
+
+		r.Commits = append(r.Commits, c)
+	}
+
+	return r
+}
+
+// testReleases returns the releases used in the tests
+func testReleases(t *testing.T) []changelog.Release {
+	t.Helper()
+
+	return []changelog.Release{
+		mkRelease(t, "v1.0.0", "2026-01-15",
+			"fix: this release is already in the existing changelog"),
+		mkRelease(t, "v0.3.1", "",
+			"fix(parser): handle a trailing newline",
+			"docs: this does not appear"),
+		mkRelease(t, "v1.1.0", "2026-10-16",
+			"feat: add a changelog writer",
+			"fix: sort the releases correctly",
+			"chore: this does not appear",
+			"feat(api)!: rename Write to WriteAll",
+			"refactor(cli): drop the -old flag\n"+
+				"\n"+
+				"BREAKING CHANGE: the -old flag has been removed;\n"+
+				"use -new instead",
+			"feat(cli): add a -new flag"),
+		mkRelease(t, "v1.1.0-rc.1", "2026-10-01",
+			"feat: add a changelog writer"),
+	}
+}
+
+func TestWrite(t *testing.T) {
+	var out bytes.Buffer
+
+	if err := changelog.Write(&out, testReleases(t)); err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+
+	gfc.Check(t, "write a new changelog", t.Name(), out.Bytes())
+}
+
+func TestMerge(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		existing string
+		gfName   string
+	}{
+		{
+			ID:       testhelper.MkID("Keep a Changelog"),
+			existing: "keepAChangelog.md",
+			gfName:   "keepAChangelog",
+		},
+		{
+			ID:       testhelper.MkID("no releases"),
+			existing: "noReleases.md",
+			gfName:   "noReleases",
+		},
+		{
+			ID:     testhelper.MkID("empty"),
+			gfName: "empty",
+		},
+	}
+
+	for _, tc := range testCases {
+		var existing []byte
+
+		if tc.existing != "" {
+			var err error
+
+			existing, err = os.ReadFile(
+				filepath.Join("testdata", "existing", tc.existing))
+			if err != nil {
+				t.Fatal(tc.IDStr(), ": cannot read the existing changelog: ",
+					err)
+			}
+		}
+
+		var out bytes.Buffer
+
+		err := changelog.Merge(&out, bytes.NewReader(existing),
+			testReleases(t))
+		if err != nil {
+			t.Fatal(tc.IDStr(), ": unexpected error: ", err)
+		}
+
+		gfc.Check(t, tc.IDStr(), t.Name()+"."+tc.gfName, out.Bytes())
+
+		var again bytes.Buffer
+
+		err = changelog.Merge(&again, bytes.NewReader(out.Bytes()),
+			testReleases(t))
+		if err != nil {
+			t.Fatal(tc.IDStr(), ": unexpected error: ", err)
+		}
+
+		testhelper.DiffString(t, tc.IDStr(), "merged twice",
+			again.String(), out.String())
+	}
+}
+
+func TestMergeReadErr(t *testing.T) {
+	tc := struct {
+		testhelper.ID
+		testhelper.ExpErr
+	}{
+		ID:     testhelper.MkID("bad reader"),
+		ExpErr: testhelper.MkExpErr("cannot read the changelog"),
+	}
+
+	err := changelog.Merge(&strings.Builder{},
+		iotest.ErrReader(errors.New("read failed")), nil)
+	testhelper.CheckExpErr(t, err, tc)
+}
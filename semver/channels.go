@@ -0,0 +1,182 @@
+package semver
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// Channels is an ordered list of pre-release channel names such as "dev",
+// "alpha", "beta" and "rc". The channel of a pre-release version is given
+// by its first pre-release ID. The order of the channels is the order in
+// which a version is expected to move through them; this need not be the
+// same as the lexical order of the names.
+type Channels struct {
+	names []string
+	rank  map[string]int
+}
+
+// NewChannels returns a new Channels with the names in the order given. It
+// returns an error if there are no names, if any name is repeated or if
+// any name is not a valid, non-numeric, pre-release ID.
+func NewChannels(names ...string) (*Channels, error) {
+	if len(names) == 0 {
+		return nil, errors.New("no pre-release channels have been given")
+	}
+
+	c := &Channels{
+		names: slices.Clone(names),
+		rank:  make(map[string]int, len(names)),
+	}
+
+	for i, name := range names {
+		if err := CheckPreRelID(name); err != nil {
+			return nil, fmt.Errorf("bad pre-release channel: %q - %w",
+				name, err)
+		}
+
+		if numericOnlyRE.MatchString(name) {
+			return nil, fmt.Errorf(
+				"bad pre-release channel: %q - it must not be numeric",
+				name)
+		}
+
+		if _, dup := c.rank[name]; dup {
+			return nil, fmt.Errorf(
+				"bad pre-release channel: %q - it appears more than once",
+				name)
+		}
+
+		c.rank[name] = i
+	}
+
+	return c, nil
+}
+
+// NewChannelsOrPanic returns a new Channels. If there were any errors it
+// will panic.
+func NewChannelsOrPanic(names ...string) *Channels {
+	c, err := NewChannels(names...)
+	if err != nil {
+		panic(err)
+	}
+
+	return c
+}
+
+// Names returns the channel names in order
+func (c Channels) Names() []string {
+	return slices.Clone(c.names)
+}
+
+// Channel returns the name of the channel of the SV and true. If the SV is
+// a release version or its first pre-release ID is not one of the channel
+// names it returns an empty string and false.
+func (c Channels) Channel(sv *SV) (string, bool) {
+	if !sv.HasPreRelIDs() {
+		return "", false
+	}
+
+	name := sv.preRelIDs[0]
+	if _, ok := c.rank[name]; !ok {
+		return "", false
+	}
+
+	return name, true
+}
+
+// notInChannelsErr returns the error for an SV which is not in any of the
+// channels
+func (c Channels) notInChannelsErr(sv *SV) error {
+	return fmt.Errorf("the version %s is not in any of the channels: %q",
+		sv, c.names)
+}
+
+// channelRank returns the position of the channel of the SV in the list
+// of channel names. A release version is ranked after all the channels. It
+// returns an error if the SV is a pre-release version but not in any of
+// the channels.
+func (c Channels) channelRank(sv *SV) (int, error) {
+	if !sv.HasPreRelIDs() {
+		return len(c.names), nil
+	}
+
+	name, ok := c.Channel(sv)
+	if !ok {
+		return 0, c.notInChannelsErr(sv)
+	}
+
+	return c.rank[name], nil
+}
+
+// Promote returns a new SV with the same version numbers and build IDs as
+// the SV but moved to the start of the next channel. So, with channels of
+// "alpha", "beta" and "rc", v2.0.0-alpha.3 is promoted to v2.0.0-beta.0.
+// The SV passed is not changed. It returns an error if the SV is not in any
+// of the channels or if it is in the last channel; a version in the last
+// channel should be promoted to a release with the Release method.
+func (c Channels) Promote(sv *SV) (*SV, error) {
+	name, ok := c.Channel(sv)
+	if !ok {
+		return nil, c.notInChannelsErr(sv)
+	}
+
+	next := c.rank[name] + 1
+	if next >= len(c.names) {
+		return nil, fmt.Errorf(
+			"the version %s is in the last channel (%q)"+
+				" and cannot be promoted",
+			sv, name)
+	}
+
+	promoted := &SV{}
+	sv.CopyInto(promoted)
+
+	err := promoted.SetPreRelIDs([]string{c.names[next], "0"})
+	if err != nil {
+		return nil, err
+	}
+
+	return promoted, nil
+}
+
+// CheckTransition checks that moving from one version to the next is
+// allowed. It returns an error if either version is a pre-release not in
+// any of the channels or if the next version does not move forward. The
+// next version moves forward if:
+//
+//   - it has greater major, minor and patch version numbers
+//   - or it has the same version numbers and a later channel (a release
+//     version is later than any channel)
+//   - or it has the same version numbers and the same channel and it has
+//     a higher precedence
+func (c Channels) CheckTransition(from, to *SV) error {
+	fromRank, err := c.channelRank(from)
+	if err != nil {
+		return err
+	}
+
+	toRank, err := c.channelRank(to)
+	if err != nil {
+		return err
+	}
+
+	cmpVal := compareCore(from, to)
+	if cmpVal == 0 {
+		cmpVal = fromRank - toRank
+	}
+
+	if cmpVal == 0 {
+		cmpVal = ComparePrecedence(from, to)
+	}
+
+	switch {
+	case cmpVal > 0:
+		return fmt.Errorf("the version %s is before %s", to, from)
+	case cmpVal == 0:
+		return fmt.Errorf("the version %s does not move forward from %s",
+			to, from)
+	}
+
+	return nil
+}
@@ -0,0 +1,255 @@
+package semver_test
+
+import (
+	"testing"
+
+	"github.com/nickwells/semver.mod/v3/semver"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestNewChannels(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		names []string
+	}{
+		{
+			ID:    testhelper.MkID("good"),
+			names: []string{"dev", "alpha", "beta", "rc"},
+		},
+		{
+			ID:     testhelper.MkID("bad - no names"),
+			ExpErr: testhelper.MkExpErr("no pre-release channels"),
+		},
+		{
+			ID:    testhelper.MkID("bad - bad ID"),
+			names: []string{"alpha", "r_c"},
+			ExpErr: testhelper.MkExpErr(`bad pre-release channel: "r_c"`,
+				"the Pre-Rel ID: 'r_c' must be "+semver.GoodIDDesc),
+		},
+		{
+			ID:    testhelper.MkID("bad - numeric"),
+			names: []string{"alpha", "2"},
+			ExpErr: testhelper.MkExpErr(
+				`bad pre-release channel: "2" - it must not be numeric`),
+		},
+		{
+			ID:    testhelper.MkID("bad - repeated"),
+			names: []string{"alpha", "beta", "alpha"},
+			ExpErr: testhelper.MkExpErr(
+				`bad pre-release channel: "alpha" - it appears more than once`),
+		},
+	}
+
+	for _, tc := range testCases {
+		c, err := semver.NewChannels(tc.names...)
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			testhelper.DiffStringSlice(t, tc.IDStr(), "names",
+				c.Names(), tc.names)
+		}
+	}
+}
+
+func TestChannel(t *testing.T) {
+	c := semver.NewChannelsOrPanic("dev", "alpha", "beta", "rc")
+
+	testCases := []struct {
+		testhelper.ID
+		svStr      string
+		expChannel string
+		expOK      bool
+	}{
+		{
+			ID:         testhelper.MkID("alpha"),
+			svStr:      "v2.0.0-alpha.3",
+			expChannel: "alpha",
+			expOK:      true,
+		},
+		{
+			ID:         testhelper.MkID("channel with no number"),
+			svStr:      "v2.0.0-rc",
+			expChannel: "rc",
+			expOK:      true,
+		},
+		{
+			ID:    testhelper.MkID("release"),
+			svStr: "v2.0.0",
+		},
+		{
+			ID:    testhelper.MkID("unknown channel"),
+			svStr: "v2.0.0-gamma.1",
+		},
+		{
+			ID:    testhelper.MkID("channel not first"),
+			svStr: "v2.0.0-1.alpha",
+		},
+	}
+
+	for _, tc := range testCases {
+		sv, err := semver.ParseSV(tc.svStr)
+		if err != nil {
+			t.Fatal("Couldn't parse the semver: ", err)
+		}
+
+		channel, ok := c.Channel(sv)
+		testhelper.DiffString(t, tc.IDStr(), "channel", channel, tc.expChannel)
+		testhelper.DiffBool(t, tc.IDStr(), "ok", ok, tc.expOK)
+	}
+}
+
+func TestPromote(t *testing.T) {
+	c := semver.NewChannelsOrPanic("dev", "alpha", "beta", "rc")
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		svStr    string
+		expSVStr string
+	}{
+		{
+			ID:       testhelper.MkID("alpha to beta"),
+			svStr:    "v2.0.0-alpha.3",
+			expSVStr: "v2.0.0-beta.0",
+		},
+		{
+			ID:       testhelper.MkID("dev to alpha, keeping build IDs"),
+			svStr:    "v2.0.0-dev.7.x+b.1",
+			expSVStr: "v2.0.0-alpha.0+b.1",
+		},
+		{
+			ID:    testhelper.MkID("bad - last channel"),
+			svStr: "v2.0.0-rc.2",
+			ExpErr: testhelper.MkExpErr(`the version v2.0.0-rc.2` +
+				` is in the last channel ("rc") and cannot be promoted`),
+		},
+		{
+			ID:    testhelper.MkID("bad - release"),
+			svStr: "v2.0.0",
+			ExpErr: testhelper.MkExpErr(
+				"the version v2.0.0 is not in any of the channels"),
+		},
+		{
+			ID:    testhelper.MkID("bad - unknown channel"),
+			svStr: "v2.0.0-gamma.0",
+			ExpErr: testhelper.MkExpErr(
+				"the version v2.0.0-gamma.0 is not in any of the channels"),
+		},
+	}
+
+	for _, tc := range testCases {
+		sv, err := semver.ParseSV(tc.svStr)
+		if err != nil {
+			t.Fatal("Couldn't parse the semver: ", err)
+		}
+
+		promoted, err := c.Promote(sv)
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			testhelper.DiffString(t, tc.IDStr(), "promoted",
+				promoted.String(), tc.expSVStr)
+		}
+
+		testhelper.DiffString(t, tc.IDStr(), "original", sv.String(), tc.svStr)
+	}
+}
+
+func TestCheckTransition(t *testing.T) {
+	c := semver.NewChannelsOrPanic("dev", "alpha", "beta", "rc")
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		fromStr string
+		toStr   string
+	}{
+		{
+			ID:      testhelper.MkID("same channel"),
+			fromStr: "v2.0.0-alpha.3",
+			toStr:   "v2.0.0-alpha.4",
+		},
+		{
+			ID:      testhelper.MkID("promotion"),
+			fromStr: "v2.0.0-alpha.3",
+			toStr:   "v2.0.0-beta.0",
+		},
+		{
+			ID:      testhelper.MkID("promotion - not lexically ordered"),
+			fromStr: "v2.0.0-beta.2",
+			toStr:   "v2.0.0-dev.0",
+			ExpErr: testhelper.MkExpErr(
+				"the version v2.0.0-dev.0 is before v2.0.0-beta.2"),
+		},
+		{
+			ID:      testhelper.MkID("dev to rc"),
+			fromStr: "v2.0.0-dev.9",
+			toStr:   "v2.0.0-rc.0",
+		},
+		{
+			ID:      testhelper.MkID("release"),
+			fromStr: "v2.0.0-rc.3",
+			toStr:   "v2.0.0",
+		},
+		{
+			ID:      testhelper.MkID("next version, earlier channel"),
+			fromStr: "v2.0.0",
+			toStr:   "v2.1.0-dev.0",
+		},
+		{
+			ID:      testhelper.MkID("bad - demotion"),
+			fromStr: "v2.0.0-beta.1",
+			toStr:   "v2.0.0-alpha.5",
+			ExpErr: testhelper.MkExpErr(
+				"the version v2.0.0-alpha.5 is before v2.0.0-beta.1"),
+		},
+		{
+			ID:      testhelper.MkID("bad - backwards in the channel"),
+			fromStr: "v2.0.0-beta.2",
+			toStr:   "v2.0.0-beta.1",
+			ExpErr: testhelper.MkExpErr(
+				"the version v2.0.0-beta.1 is before v2.0.0-beta.2"),
+		},
+		{
+			ID:      testhelper.MkID("bad - release to pre-release"),
+			fromStr: "v2.0.0",
+			toStr:   "v2.0.0-rc.9",
+			ExpErr: testhelper.MkExpErr(
+				"the version v2.0.0-rc.9 is before v2.0.0"),
+		},
+		{
+			ID:      testhelper.MkID("bad - earlier version"),
+			fromStr: "v2.0.0-dev.0",
+			toStr:   "v1.9.9",
+			ExpErr: testhelper.MkExpErr(
+				"the version v1.9.9 is before v2.0.0-dev.0"),
+		},
+		{
+			ID:      testhelper.MkID("bad - no change"),
+			fromStr: "v2.0.0-rc.1",
+			toStr:   "v2.0.0-rc.1+b",
+			ExpErr: testhelper.MkExpErr(
+				"the version v2.0.0-rc.1+b does not move forward" +
+					" from v2.0.0-rc.1"),
+		},
+		{
+			ID:      testhelper.MkID("bad - unknown channel"),
+			fromStr: "v2.0.0-rc.1",
+			toStr:   "v2.1.0-gamma.1",
+			ExpErr: testhelper.MkExpErr(
+				"the version v2.1.0-gamma.1 is not in any of the channels"),
+		},
+	}
+
+	for _, tc := range testCases {
+		from, err := semver.ParseSV(tc.fromStr)
+		if err != nil {
+			t.Fatal("Couldn't parse the semver: ", err)
+		}
+
+		to, err := semver.ParseSV(tc.toStr)
+		if err != nil {
+			t.Fatal("Couldn't parse the semver: ", err)
+		}
+
+		err = c.CheckTransition(from, to)
+		testhelper.CheckExpErr(t, err, tc)
+	}
+}
@@ -0,0 +1,281 @@
+package semver
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Coercion is a set of the corrections that Coerce is allowed to make to a
+// version string. The values can be combined with the '|' operator.
+type Coercion uint
+
+const (
+	// CoerceUpperV allows a leading 'V' to be changed to a 'v'
+	CoerceUpperV Coercion = 1 << iota
+	// CoerceSpace allows leading and trailing white space to be removed
+	CoerceSpace
+	// CoerceSurroundingText allows any text before or after the version to
+	// be removed, for instance "release-1.5" gives v1.5.0 (if
+	// CoerceMissingParts is also allowed)
+	CoerceSurroundingText
+	// CoerceMissingParts allows a missing minor or patch version to be
+	// replaced with 0
+	CoerceMissingParts
+	// CoerceLeadingZeros allows leading zeros to be removed from the
+	// major, minor and patch versions and from numeric pre-release IDs
+	CoerceLeadingZeros
+	// CoerceExtraParts allows any version numbers after the patch version
+	// to be moved into the build IDs, for instance "1.2.3.4" gives
+	// v1.2.3+4
+	CoerceExtraParts
+
+	// CoerceAll allows all the corrections
+	CoerceAll = CoerceUpperV | CoerceSpace | CoerceSurroundingText |
+		CoerceMissingParts | CoerceLeadingZeros | CoerceExtraParts
+)
+
+var coercionNames = []struct {
+	c    Coercion
+	name string
+}{
+	{CoerceUpperV, "UpperV"},
+	{CoerceSpace, "Space"},
+	{CoerceSurroundingText, "SurroundingText"},
+	{CoerceMissingParts, "MissingParts"},
+	{CoerceLeadingZeros, "LeadingZeros"},
+	{CoerceExtraParts, "ExtraParts"},
+}
+
+// String returns the names of the corrections in the Coercion separated by
+// '|'
+func (c Coercion) String() string {
+	names := []string{}
+
+	for _, cn := range coercionNames {
+		if c&cn.c != 0 {
+			names = append(names, cn.name)
+			c &^= cn.c
+		}
+	}
+
+	if c != 0 {
+		names = append(names, fmt.Sprintf("Coercion(%#x)", uint(c)))
+	}
+
+	return strings.Join(names, "|")
+}
+
+// Correction records a correction made by Coerce
+type Correction struct {
+	// Coercion is the kind of correction made
+	Coercion Coercion
+	// Desc describes the correction
+	Desc string
+}
+
+// String returns the description of the Correction
+func (c Correction) String() string { return c.Desc }
+
+var coerceRE = regexp.MustCompile(
+	`([vV]?)([0-9]+(?:\.[0-9]+)*)` +
+		`(?:-([-0-9A-Za-z.]*))?` +
+		`(?:\+([-0-9A-Za-z.]*))?`)
+
+// These are the indexes of the sub-matches of coerceRE
+const (
+	coerceREPrefixIdx = iota + 1
+	coerceRENumsIdx
+	coerceREPreRelIdx
+	coerceREBuildIdx
+)
+
+// coercer holds the state of a call to Coerce
+type coercer struct {
+	input       string
+	allowed     Coercion
+	corrections []Correction
+}
+
+// correct records the correction if it is allowed and returns an error if
+// it is not
+func (cr *coercer) correct(c Coercion, format string, args ...any) error {
+	desc := fmt.Sprintf(format, args...)
+
+	if cr.allowed&c == 0 {
+		return fmt.Errorf("bad %s: %q - the correction is not allowed: %s",
+			Name, cr.input, desc)
+	}
+
+	cr.corrections = append(cr.corrections,
+		Correction{Coercion: c, Desc: desc})
+
+	return nil
+}
+
+// trimLeadingZeros removes any leading zeros from the number, recording the
+// correction
+func (cr *coercer) trimLeadingZeros(num, name string) (string, error) {
+	trimmed := strings.TrimLeft(num, "0")
+	if trimmed == "" {
+		trimmed = "0"
+	}
+
+	if trimmed != num {
+		err := cr.correct(CoerceLeadingZeros,
+			"removed the leading zeros from the %s: %q", name, num)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return trimmed, nil
+}
+
+// trimSurroundingText records the removal of the text before and after the
+// version, describing only the non-empty parts
+func (cr *coercer) trimSurroundingText(leading, trailing string) error {
+	switch {
+	case leading != "" && trailing != "":
+		return cr.correct(CoerceSurroundingText,
+			"removed the surrounding text: %q and %q", leading, trailing)
+	case leading != "":
+		return cr.correct(CoerceSurroundingText,
+			"removed the leading text: %q", leading)
+	case trailing != "":
+		return cr.correct(CoerceSurroundingText,
+			"removed the trailing text: %q", trailing)
+	}
+
+	return nil
+}
+
+// Coerce converts the string into an SV, making any corrections needed to
+// turn a real-world version string into a well-formed semantic version
+// ID. Only the corrections in the allowed Coercion will be made. It returns
+// the SV and a list of the corrections that were made; if the string is
+// already a well-formed semantic version ID (with or without the leading
+// 'v') the list is empty. An error is returned if the string cannot be
+// made into an SV using only the allowed corrections.
+//
+//nolint:cyclop
+func Coerce(s string, allowed Coercion) (*SV, []Correction, error) {
+	sv, strictErr := parseOptPfx(s)
+	if strictErr == nil {
+		return sv, []Correction{}, nil
+	}
+
+	cr := &coercer{input: s, allowed: allowed, corrections: []Correction{}}
+
+	str := s
+	if trimmed := strings.TrimSpace(str); trimmed != str {
+		if err := cr.correct(CoerceSpace,
+			"removed the surrounding white space"); err != nil {
+			return nil, nil, err
+		}
+
+		str = trimmed
+	}
+
+	loc := coerceRE.FindStringSubmatchIndex(str)
+	if loc == nil {
+		return nil, nil, strictErr
+	}
+
+	if err := cr.trimSurroundingText(str[:loc[0]], str[loc[1]:]); err != nil {
+		return nil, nil, err
+	}
+
+	subMatch := func(i int) (string, bool) {
+		if loc[2*i] < 0 {
+			return "", false
+		}
+
+		return str[loc[2*i]:loc[2*i+1]], true
+	}
+
+	if pfx, _ := subMatch(coerceREPrefixIdx); pfx == "V" {
+		if err := cr.correct(CoerceUpperV,
+			"changed the leading 'V' to 'v'"); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	numStr, _ := subMatch(coerceRENumsIdx)
+	nums := strings.Split(numStr, semverPartSeparator)
+
+	if len(nums) < semverVsnPartCount {
+		missing := "patch version"
+		if len(nums) == 1 {
+			missing = "minor and patch versions"
+		}
+
+		if err := cr.correct(CoerceMissingParts,
+			"added the missing %s", missing); err != nil {
+			return nil, nil, err
+		}
+
+		for len(nums) < semverVsnPartCount {
+			nums = append(nums, "0")
+		}
+	}
+
+	var buildIDs []string
+
+	if len(nums) > semverVsnPartCount {
+		extra := nums[semverVsnPartCount:]
+		nums = nums[:semverVsnPartCount]
+
+		if err := cr.correct(CoerceExtraParts,
+			"moved the extra version numbers into the build IDs: %q",
+			strings.Join(extra, semverPartSeparator)); err != nil {
+			return nil, nil, err
+		}
+
+		buildIDs = extra
+	}
+
+	vNums := [semverVsnPartCount]vNum{}
+
+	for i, name := range []string{"major", "minor", "patch"} {
+		num, err := cr.trimLeadingZeros(nums[i], name+" version")
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if vNums[i], err = strToVNum(num, name); err != nil {
+			return nil, nil, fmt.Errorf("bad %s: %q - %w", Name, s, err)
+		}
+	}
+
+	var prIDs []string
+
+	if prStr, ok := subMatch(coerceREPreRelIdx); ok {
+		prIDs = strings.Split(prStr, semverPartSeparator)
+
+		for i, id := range prIDs {
+			if !numericOnlyRE.MatchString(id) {
+				continue
+			}
+
+			var err error
+
+			prIDs[i], err = cr.trimLeadingZeros(id, "pre-release ID")
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	if bStr, ok := subMatch(coerceREBuildIdx); ok {
+		buildIDs = append(buildIDs,
+			strings.Split(bStr, semverPartSeparator)...)
+	}
+
+	sv, err := newSV(vNums[0], vNums[1], vNums[2], prIDs, buildIDs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("bad %s: %q - %w", Name, s, err)
+	}
+
+	return sv, cr.corrections, nil
+}
@@ -0,0 +1,263 @@
+package semver_test
+
+import (
+	"testing"
+
+	"github.com/nickwells/semver.mod/v3/semver"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestCoerce(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		s              string
+		allowed        semver.Coercion
+		expSVStr       string
+		expCorrections []string
+	}{
+		{
+			ID:             testhelper.MkID("already good"),
+			s:              "v1.2.3-rc.1+b",
+			expSVStr:       "v1.2.3-rc.1+b",
+			expCorrections: []string{},
+		},
+		{
+			ID:             testhelper.MkID("already good - no prefix"),
+			s:              "1.2.3",
+			expSVStr:       "v1.2.3",
+			expCorrections: []string{},
+		},
+		{
+			ID:       testhelper.MkID("upper V and missing patch"),
+			s:        "V1.2",
+			allowed:  semver.CoerceAll,
+			expSVStr: "v1.2.0",
+			expCorrections: []string{
+				"changed the leading 'V' to 'v'",
+				"added the missing patch version",
+			},
+		},
+		{
+			ID:       testhelper.MkID("missing minor and patch"),
+			s:        "v7",
+			allowed:  semver.CoerceMissingParts,
+			expSVStr: "v7.0.0",
+			expCorrections: []string{
+				"added the missing minor and patch versions",
+			},
+		},
+		{
+			ID:       testhelper.MkID("extra parts"),
+			s:        "1.2.3.4",
+			allowed:  semver.CoerceExtraParts,
+			expSVStr: "v1.2.3+4",
+			expCorrections: []string{
+				`moved the extra version numbers into the build IDs: "4"`,
+			},
+		},
+		{
+			ID:       testhelper.MkID("extra parts and build IDs"),
+			s:        "1.2.3.4.05+x",
+			allowed:  semver.CoerceExtraParts,
+			expSVStr: "v1.2.3+4.05.x",
+			expCorrections: []string{
+				`moved the extra version numbers into the build IDs: "4.05"`,
+			},
+		},
+		{
+			ID:       testhelper.MkID("surrounding text"),
+			s:        "release-1.5",
+			allowed:  semver.CoerceAll,
+			expSVStr: "v1.5.0",
+			expCorrections: []string{
+				`removed the leading text: "release-"`,
+				"added the missing patch version",
+			},
+		},
+		{
+			ID:       testhelper.MkID("surrounding text - trailing"),
+			s:        "v2.0.0 (linux)",
+			allowed:  semver.CoerceSurroundingText,
+			expSVStr: "v2.0.0",
+			expCorrections: []string{
+				`removed the trailing text: " (linux)"`,
+			},
+		},
+		{
+			ID:       testhelper.MkID("surrounding text - both ends"),
+			s:        "app v2.0.0 (linux)",
+			allowed:  semver.CoerceSurroundingText,
+			expSVStr: "v2.0.0",
+			expCorrections: []string{
+				`removed the surrounding text: "app " and " (linux)"`,
+			},
+		},
+		{
+			ID:       testhelper.MkID("surrounding space"),
+			s:        " v2.0.0 ",
+			allowed:  semver.CoerceSpace,
+			expSVStr: "v2.0.0",
+			expCorrections: []string{
+				"removed the surrounding white space",
+			},
+		},
+		{
+			ID:       testhelper.MkID("surrounding space and text"),
+			s:        " app v2.0.0\n",
+			allowed:  semver.CoerceSpace | semver.CoerceSurroundingText,
+			expSVStr: "v2.0.0",
+			expCorrections: []string{
+				"removed the surrounding white space",
+				`removed the leading text: "app "`,
+			},
+		},
+		{
+			ID:       testhelper.MkID("upper V"),
+			s:        "V1.2.3",
+			allowed:  semver.CoerceUpperV,
+			expSVStr: "v1.2.3",
+			expCorrections: []string{
+				"changed the leading 'V' to 'v'",
+			},
+		},
+		{
+			ID:       testhelper.MkID("leading zeros"),
+			s:        "1.02.3-rc.007",
+			allowed:  semver.CoerceLeadingZeros,
+			expSVStr: "v1.2.3-rc.7",
+			expCorrections: []string{
+				`removed the leading zeros from the minor version: "02"`,
+				`removed the leading zeros from the pre-release ID: "007"`,
+			},
+		},
+		{
+			ID:       testhelper.MkID("leading zeros - all zero"),
+			s:        "v00.1.0",
+			allowed:  semver.CoerceLeadingZeros,
+			expSVStr: "v0.1.0",
+			expCorrections: []string{
+				`removed the leading zeros from the major version: "00"`,
+			},
+		},
+		{
+			ID:      testhelper.MkID("bad - upper V not allowed"),
+			s:       "V1.2.3",
+			allowed: semver.CoerceAll &^ semver.CoerceUpperV,
+			ExpErr: testhelper.MkExpErr(
+				`bad semantic version ID: "V1.2.3"`,
+				"the correction is not allowed:",
+				"changed the leading 'V' to 'v'"),
+		},
+		{
+			ID: testhelper.MkID("bad - surrounding space not allowed"),
+			s:  " v2.0.0",
+			ExpErr: testhelper.MkExpErr(
+				"the correction is not allowed:",
+				"removed the surrounding white space"),
+		},
+		{
+			ID:      testhelper.MkID("bad - surrounding space, only text allowed"),
+			s:       " v2.0.0 ",
+			allowed: semver.CoerceSurroundingText,
+			ExpErr: testhelper.MkExpErr(
+				"the correction is not allowed:",
+				"removed the surrounding white space"),
+		},
+		{
+			ID:      testhelper.MkID("bad - surrounding text, only space allowed"),
+			s:       " app v2.0.0",
+			allowed: semver.CoerceSpace,
+			ExpErr: testhelper.MkExpErr(
+				"the correction is not allowed:",
+				`removed the leading text: "app "`),
+		},
+		{
+			ID:      testhelper.MkID("bad - extra parts not allowed"),
+			s:       "1.2.3.4",
+			allowed: semver.CoerceAll &^ semver.CoerceExtraParts,
+			ExpErr: testhelper.MkExpErr(
+				"the correction is not allowed:",
+				"moved the extra version numbers into the build IDs"),
+		},
+		{
+			ID:      testhelper.MkID("bad - leading zeros not allowed"),
+			s:       "1.02.3",
+			allowed: semver.CoerceAll &^ semver.CoerceLeadingZeros,
+			ExpErr: testhelper.MkExpErr(
+				"the correction is not allowed:",
+				`removed the leading zeros from the minor version: "02"`),
+		},
+		{
+			ID:      testhelper.MkID("bad - missing parts not allowed"),
+			s:       "1.2",
+			allowed: semver.CoerceAll &^ semver.CoerceMissingParts,
+			ExpErr: testhelper.MkExpErr(
+				"the correction is not allowed:",
+				"added the missing patch version"),
+		},
+		{
+			ID:      testhelper.MkID("bad - no version"),
+			s:       "release",
+			allowed: semver.CoerceAll,
+			ExpErr: testhelper.MkExpErr(
+				"bad semantic version ID - it cannot be split"),
+		},
+		{
+			ID:      testhelper.MkID("bad - bad pre-release ID"),
+			s:       "1.2.3-a..b",
+			allowed: semver.CoerceAll,
+			ExpErr: testhelper.MkExpErr(
+				`bad semantic version ID: "1.2.3-a..b"`,
+				"the Pre-Rel ID: '' must be"),
+		},
+	}
+
+	for _, tc := range testCases {
+		sv, corrections, err := semver.Coerce(tc.s, tc.allowed)
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			testhelper.DiffString(t, tc.IDStr(), "SV", sv.String(), tc.expSVStr)
+
+			got := make([]string, 0, len(corrections))
+			for _, c := range corrections {
+				got = append(got, c.String())
+			}
+
+			testhelper.DiffStringSlice(t, tc.IDStr(), "corrections",
+				got, tc.expCorrections)
+		}
+	}
+}
+
+func TestCoercionString(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		c      semver.Coercion
+		expStr string
+	}{
+		{
+			ID:     testhelper.MkID("none"),
+			expStr: "",
+		},
+		{
+			ID:     testhelper.MkID("one"),
+			c:      semver.CoerceUpperV,
+			expStr: "UpperV",
+		},
+		{
+			ID: testhelper.MkID("all"),
+			c:  semver.CoerceAll,
+			expStr: "UpperV|Space|SurroundingText|" +
+				"MissingParts|LeadingZeros|ExtraParts",
+		},
+		{
+			ID:     testhelper.MkID("unknown"),
+			c:      semver.CoerceSpace | 1<<10,
+			expStr: "Space|Coercion(0x400)",
+		},
+	}
+
+	for _, tc := range testCases {
+		testhelper.DiffString(t, tc.IDStr(), "string", tc.c.String(), tc.expStr)
+	}
+}
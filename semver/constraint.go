@@ -0,0 +1,429 @@
+package semver
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ConstraintName is the name used to describe a version constraint in error
+// messages
+const ConstraintName = "version constraint"
+
+const (
+	constraintAltSeparator  = "||"
+	constraintJoinAlts      = " " + constraintAltSeparator + " "
+	constraintJoinClauses   = " "
+	cargoReqClauseSeparator = ","
+	cargoReqJoinClauses     = cargoReqClauseSeparator + " "
+)
+
+// Dialect identifies the syntax in which a Constraint is written
+type Dialect int
+
+const (
+	// DialectNPM is the syntax used by npm. Clauses are separated by white
+	// space and alternatives by "||". A version without an operator must be
+	// matched exactly.
+	DialectNPM Dialect = iota
+	// DialectCargo is the syntax used by Cargo for Rust crate
+	// requirements. Clauses are separated by commas and there are no
+	// alternatives, hyphen ranges or "!=" operator. A version without an
+	// operator is taken as a caret range unless it has a wildcard.
+	DialectCargo
+)
+
+// op represents the comparison operator of a single clause in a constraint
+type op int
+
+const (
+	opEQ op = iota
+	opNE
+	opLT
+	opLE
+	opGT
+	opGE
+)
+
+// opStrings maps the operators to their string forms
+var opStrings = []struct {
+	op op
+	s  string
+}{
+	{opEQ, "="},
+	{opNE, "!="},
+	{opLT, "<"},
+	{opLE, "<="},
+	{opGT, ">"},
+	{opGE, ">="},
+}
+
+// String returns the string form of the operator
+func (o op) String() string {
+	for _, os := range opStrings {
+		if os.op == o {
+			return os.s
+		}
+	}
+
+	return fmt.Sprintf("op(%d)", int(o))
+}
+
+// clauseOps lists the operators that can start a clause of a
+// constraint. The order of the entries is significant - where one operator
+// is a prefix of another the longer form must come first so that it is
+// matched first.
+var clauseOps = []string{
+	"!=", "<=", ">=", "~>",
+	"<", ">", "=", "^", "~",
+}
+
+// cutClauseOp removes any leading operator from the string returning the
+// operator and the remainder of the string. If there is no leading operator
+// then the operator returned is the empty string.
+func cutClauseOp(s string) (string, string) {
+	for _, o := range clauseOps {
+		if rest, ok := strings.CutPrefix(s, o); ok {
+			return o, rest
+		}
+	}
+
+	return "", s
+}
+
+// comparator is a single clause of a constraint - an operator and the
+// version it compares against
+type comparator struct {
+	op op
+	sv *SV
+}
+
+// check returns true if the SV satisfies the comparator. The pre-release
+// rule is not applied here, it is applied to the whole comparator set
+func (c comparator) check(sv *SV) bool {
+	cmp := Compare(sv, c.sv)
+
+	switch c.op {
+	case opEQ:
+		return cmp == 0
+	case opNE:
+		return cmp != 0
+	case opLT:
+		return cmp < 0
+	case opLE:
+		return cmp <= 0
+	case opGT:
+		return cmp > 0
+	case opGE:
+		return cmp >= 0
+	}
+
+	return false
+}
+
+// String returns the string form of the comparator
+func (c comparator) String() string {
+	return c.op.String() + strings.TrimPrefix(c.sv.String(), semverPrefix)
+}
+
+// comparatorSet is a collection of comparators all of which must be
+// satisfied
+type comparatorSet []comparator
+
+// check returns true if the SV satisfies every comparator in the set. An SV
+// with pre-release IDs will only satisfy the set if, in addition, some
+// comparator in the set has pre-release IDs and the same major, minor and
+// patch versions as the SV.
+func (cs comparatorSet) check(sv *SV) bool {
+	for _, c := range cs {
+		if !c.check(sv) {
+			return false
+		}
+	}
+
+	if !sv.HasPreRelIDs() {
+		return true
+	}
+
+	for _, c := range cs {
+		if c.sv.HasPreRelIDs() &&
+			compareCore(c.sv, sv) == 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// String returns the string form of the comparator set. The clauses are
+// separated as appropriate for the dialect.
+func (cs comparatorSet) String(d Dialect) string {
+	parts := make([]string, 0, len(cs))
+	for _, c := range cs {
+		parts = append(parts, c.String())
+	}
+
+	if d == DialectCargo {
+		return strings.Join(parts, cargoReqJoinClauses)
+	}
+
+	return strings.Join(parts, constraintJoinClauses)
+}
+
+// Constraint holds a parsed version constraint. A constraint is made up of
+// one or more alternatives separated by "||" and the constraint is satisfied
+// if any of the alternatives is satisfied. Each alternative is made up of one
+// or more clauses separated by white space, all of which must be satisfied
+// for the alternative to be satisfied. Each clause is an optional comparison
+// operator (one of =, !=, <, <=, > or >=) followed by a semantic version
+// (the leading 'v' is optional). If the operator is missing then '=' is
+// assumed. For instance:
+//
+//	>=1.2.0 <2.0.0 || >=3.0.0
+//
+// The npm-style range operators are also supported. These are converted into
+// the equivalent primitive clauses which are shown by the String method.
+//
+//   - X-ranges: a version number may be missing or given as 'x', 'X' or '*'
+//     and it will match any value. So "1.2.x" and "1.2" are both the same as
+//     ">=1.2.0 <1.3.0" and "*" matches any version. Partial versions may
+//     also be used with the comparison operators, so "<=1.2" is the same as
+//     "<1.3.0" and ">1" is the same as ">=2.0.0"
+//   - Caret ranges: "^1.2.3" allows changes which do not modify the
+//     left-most non-zero version number. So it is the same as ">=1.2.3
+//     <2.0.0" whereas "^0.2.3" is the same as ">=0.2.3 <0.3.0" and "^0.0.3"
+//     is the same as ">=0.0.3 <0.0.4"
+//   - Tilde ranges: "~1.2.3" allows patch-level changes if the minor version
+//     is given and minor-level changes if not. So it is the same as ">=1.2.3
+//     <1.3.0" and "~1" is the same as ">=1.0.0 <2.0.0". "~>" is a synonym
+//     for "~"
+//   - Hyphen ranges: "1.2.3 - 2.3.4" is the same as ">=1.2.3 <=2.3.4". A
+//     partial upper bound matches any version with the given version numbers
+//     so "1.2.3 - 2.3" is the same as ">=1.2.3 <2.4.0"
+//
+// Versions are compared by precedence so build IDs are ignored.
+//
+// A version with pre-release IDs will only satisfy an alternative if some
+// clause in that alternative also has pre-release IDs and has the same
+// major, minor and patch versions. This means that, for instance,
+// v1.4.2-rc.1 does not satisfy ">=1.2.0 <2.0.0" but it does satisfy
+// ">=1.4.2-rc.0 <2.0.0".
+//
+// The above describes the default, npm, dialect. Constraints can also be
+// written in the Cargo dialect, see ParseCargoReq.
+type Constraint struct {
+	dialect Dialect
+	alts    []comparatorSet
+}
+
+// ParseConstraint parses the string, written in the npm dialect, into a
+// Constraint. It will return a pointer to a properly constructed Constraint
+// and a nil error if the constraint is well-formed or a nil pointer and an
+// error otherwise
+func ParseConstraint(s string) (*Constraint, error) {
+	return ParseConstraintDialect(s, DialectNPM)
+}
+
+// ParseCargoReq parses the string, written in the Cargo dialect, into a
+// Constraint. The clauses are separated by commas, all of which must be
+// satisfied. Each clause is an optional operator (one of =, <, <=, >, >=, ~
+// or ^) followed by a possibly partial version. If there is no operator
+// then '^' is assumed unless the version has a wildcard ("1.2.*"), in which
+// case it is an x-range; a version with a wildcard cannot have an
+// operator. For instance:
+//
+//	>=1.2.0, <1.5
+//
+// The operators have the same meanings as in the npm dialect, as do the
+// rules for matching pre-release versions.
+func ParseCargoReq(s string) (*Constraint, error) {
+	return ParseConstraintDialect(s, DialectCargo)
+}
+
+// ParseConstraintDialect parses the string, written in the given dialect,
+// into a Constraint. It will return a pointer to a properly constructed
+// Constraint and a nil error if the constraint is well-formed or a nil
+// pointer and an error otherwise
+func ParseConstraintDialect(s string, d Dialect) (*Constraint, error) {
+	c := &Constraint{dialect: d}
+
+	if d == DialectCargo {
+		cs, err := parseCargoComparatorSet(s)
+		if err != nil {
+			return nil, fmt.Errorf("bad %s: %q - %w", ConstraintName, s, err)
+		}
+
+		c.alts = append(c.alts, cs)
+
+		return c, nil
+	}
+
+	for alt := range strings.SplitSeq(s, constraintAltSeparator) {
+		cs, err := parseComparatorSet(alt)
+		if err != nil {
+			return nil, fmt.Errorf("bad %s: %q - %w", ConstraintName, s, err)
+		}
+
+		c.alts = append(c.alts, cs)
+	}
+
+	return c, nil
+}
+
+// ParseConstraintOrPanic parses the string into a Constraint. If there were
+// any errors it will panic.
+func ParseConstraintOrPanic(s string) *Constraint {
+	c, err := ParseConstraint(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return c
+}
+
+// parseComparatorSet parses the (white-space separated) clauses of a single
+// alternative of a constraint
+func parseComparatorSet(s string) (comparatorSet, error) {
+	tokens := strings.Fields(s)
+	if len(tokens) == 0 {
+		return nil, errors.New("an alternative is empty")
+	}
+
+	cs := comparatorSet{}
+
+	for i := 0; i < len(tokens); i++ {
+		if tokens[i] == hyphenRangeSeparator {
+			if i+1 < len(tokens) {
+				return nil, fmt.Errorf(
+					"the hyphen range ending %q has no lower bound",
+					tokens[i+1])
+			}
+
+			return nil, errors.New("a hyphen range has no bounds")
+		}
+
+		if i+1 < len(tokens) && tokens[i+1] == hyphenRangeSeparator {
+			if i+2 >= len(tokens) || tokens[i+2] == hyphenRangeSeparator {
+				return nil, fmt.Errorf(
+					"the hyphen range starting %q has no upper bound",
+					tokens[i])
+			}
+
+			clauses, err := desugarHyphenRange(tokens[i], tokens[i+2])
+			if err != nil {
+				return nil, err
+			}
+
+			cs = append(cs, clauses...)
+			i += 2
+
+			continue
+		}
+
+		o, vsn := cutClauseOp(tokens[i])
+		if o != "" && vsn == "" {
+			// the operator is separated from the version by white space
+			i++
+			if i >= len(tokens) {
+				return nil, fmt.Errorf("the operator %q has no version", o)
+			}
+
+			vsn = tokens[i]
+		}
+
+		clauses, err := desugar(o, vsn)
+		if err != nil {
+			return nil, err
+		}
+
+		cs = append(cs, clauses...)
+	}
+
+	return cs, nil
+}
+
+// parseCargoComparatorSet parses the (comma separated) clauses of a
+// constraint written in the Cargo dialect
+func parseCargoComparatorSet(s string) (comparatorSet, error) {
+	cs := comparatorSet{}
+
+	for clause := range strings.SplitSeq(s, cargoReqClauseSeparator) {
+		clause = strings.TrimSpace(clause)
+		if clause == "" {
+			return nil, errors.New("a clause is empty")
+		}
+
+		o, vsn := cutClauseOp(clause)
+		switch o {
+		case "!=", "~>":
+			return nil, fmt.Errorf("the operator %q is not allowed", o)
+		case "":
+			if !hasWildcard(vsn) {
+				o = "^" // a bare version is a caret range
+			}
+		default:
+			if hasWildcard(vsn) {
+				return nil, fmt.Errorf(
+					"the clause %q has both an operator and a wildcard",
+					clause)
+			}
+		}
+
+		vsn = strings.TrimSpace(vsn)
+		if vsn == "" {
+			return nil, fmt.Errorf("the operator %q has no version", o)
+		}
+
+		if strings.ContainsFunc(vsn, unicode.IsSpace) {
+			return nil, fmt.Errorf(
+				"the clause %q has more than one version"+
+					" (clauses must be separated by %q)",
+				clause, cargoReqClauseSeparator)
+		}
+
+		clauses, err := desugar(o, vsn)
+		if err != nil {
+			return nil, err
+		}
+
+		cs = append(cs, clauses...)
+	}
+
+	return cs, nil
+}
+
+// parseOptPfx parses the string as a semantic version, the leading 'v' is
+// optional
+func parseOptPfx(s string) (*SV, error) {
+	if strings.HasPrefix(s, semverPrefix) {
+		return ParseSV(s)
+	}
+
+	return ParseStrictSV(s)
+}
+
+// Check returns true if the SV satisfies the Constraint, false otherwise
+func (c Constraint) Check(sv *SV) bool {
+	if sv == nil {
+		return false
+	}
+
+	for _, cs := range c.alts {
+		if cs.check(sv) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// String returns a string representation of the Constraint
+func (c Constraint) String() string {
+	parts := make([]string, 0, len(c.alts))
+	for _, cs := range c.alts {
+		parts = append(parts, cs.String(c.dialect))
+	}
+
+	return strings.Join(parts, constraintJoinAlts)
+}
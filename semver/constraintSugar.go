@@ -0,0 +1,261 @@
+package semver
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+const hyphenRangeSeparator = "-"
+
+// partialSV holds a possibly incomplete version as given in a constraint,
+// such as "1.2", "1.x" or "*". The count records how many of the version
+// numbers were given, any missing or wildcard numbers are zero.
+type partialSV struct {
+	sv    *SV
+	count int
+}
+
+// isWildcard returns true if the string is one of the values that can be
+// used in a constraint to stand for any version number
+func isWildcard(s string) bool {
+	return s == "x" || s == "X" || s == "*"
+}
+
+// hasWildcard returns true if any of the version numbers in the string is a
+// wildcard
+func hasWildcard(s string) bool {
+	return slices.ContainsFunc(strings.Split(s, semverPartSeparator), isWildcard)
+}
+
+// parsePartialSV parses the string as a possibly incomplete version. The
+// leading 'v' is optional. Any version numbers after the first missing or
+// wildcard number must also be missing or wildcards. Pre-release and build
+// IDs may only be given if all the version numbers are present.
+func parsePartialSV(s string) (partialSV, error) {
+	if s == "" {
+		return partialSV{}, fmt.Errorf("the %s is missing", Name)
+	}
+
+	core, _, hasSuffix := strings.Cut(
+		strings.TrimPrefix(s, semverPrefix),
+		semverBuildIDsSeparator)
+	core, _, hasPreRel := strings.Cut(core, semverPreRelIDsSeparator)
+	hasSuffix = hasSuffix || hasPreRel
+
+	parts := strings.Split(core, semverPartSeparator)
+	if len(parts) > semverVsnPartCount {
+		return partialSV{}, fmt.Errorf("the %s: %q has too many parts", Name, s)
+	}
+
+	count := 0
+	for count < len(parts) && !isWildcard(parts[count]) {
+		count++
+	}
+
+	for _, p := range parts[count:] {
+		if !isWildcard(p) {
+			return partialSV{},
+				fmt.Errorf("the %s: %q has a number after a wildcard", Name, s)
+		}
+	}
+
+	if count == semverVsnPartCount {
+		sv, err := parseOptPfx(s)
+		if err != nil {
+			return partialSV{}, err
+		}
+
+		return partialSV{sv: sv, count: count}, nil
+	}
+
+	if hasSuffix {
+		return partialSV{}, fmt.Errorf(
+			"the %s: %q is incomplete and so cannot have"+
+				" pre-release or build IDs",
+			Name, s)
+	}
+
+	names := []string{"major", "minor", "patch"}
+	vNums := [semverVsnPartCount]vNum{}
+
+	for i := range count {
+		var err error
+
+		vNums[i], err = strToVNum(parts[i], names[i])
+		if err != nil {
+			return partialSV{}, err
+		}
+	}
+
+	return partialSV{
+		sv:    mkReleaseSV(vNums[0], vNums[1], vNums[2]),
+		count: count,
+	}, nil
+}
+
+// mkReleaseSV returns a new SV with the given version numbers and no
+// pre-release or build IDs
+func mkReleaseSV(major, minor, patch vNum) *SV {
+	return &SV{
+		major:      major,
+		minor:      minor,
+		patch:      patch,
+		hasBeenSet: true,
+	}
+}
+
+// lower returns the lowest version matched by the partial version
+func (p partialSV) lower() *SV {
+	return p.sv
+}
+
+// upper returns the lowest version above all the versions matched by the
+// partial version (or, for a complete version, the next minor version). This
+// is also the upper bound of a tilde range. It must not be called for a
+// partial version with no version numbers.
+func (p partialSV) upper() *SV {
+	if p.count == 1 {
+		return mkReleaseSV(p.sv.major.incr(), "0", "0")
+	}
+
+	return mkReleaseSV(p.sv.major, p.sv.minor.incr(), "0")
+}
+
+// caretUpper returns the lowest version above all the versions matched by
+// the caret range on the partial version. This is the next version which
+// changes the left-most non-zero version number. If the major and minor
+// versions are both zero and the patch is not given then it is the next
+// minor version.
+func (p partialSV) caretUpper() *SV {
+	switch {
+	case !p.sv.major.isZero() || p.count == 1:
+		return mkReleaseSV(p.sv.major.incr(), "0", "0")
+	case !p.sv.minor.isZero() || p.count == 2:
+		return mkReleaseSV("0", p.sv.minor.incr(), "0")
+	default:
+		return mkReleaseSV("0", "0", p.sv.patch.incr())
+	}
+}
+
+// anyVersion returns a comparator set matching any version
+func anyVersion() comparatorSet {
+	return comparatorSet{{op: opGE, sv: mkReleaseSV("0", "0", "0")}}
+}
+
+// noVersion returns a comparator set matching no version
+func noVersion() comparatorSet {
+	return comparatorSet{{op: opLT, sv: mkReleaseSV("0", "0", "0")}}
+}
+
+// rangeOf returns a comparator set matching versions greater than or equal
+// to lower and less than upper
+func rangeOf(lower, upper *SV) comparatorSet {
+	return comparatorSet{
+		{op: opGE, sv: lower},
+		{op: opLT, sv: upper},
+	}
+}
+
+// desugar converts the operator and (possibly partial) version into the
+// equivalent primitive comparators
+//
+//nolint:cyclop
+func desugar(o, vsn string) (comparatorSet, error) {
+	p, err := parsePartialSV(vsn)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.count == semverVsnPartCount {
+		switch o {
+		case "^":
+			return rangeOf(p.lower(), p.caretUpper()), nil
+		case "~", "~>":
+			return rangeOf(p.lower(), p.upper()), nil
+		}
+
+		return comparatorSet{{op: opFromString(o), sv: p.sv}}, nil
+	}
+
+	if p.count == 0 {
+		switch o {
+		case "<", ">":
+			return noVersion(), nil
+		case "!=":
+			return nil, fmt.Errorf("the operator %q needs a complete %s, not %q",
+				o, Name, vsn)
+		}
+
+		return anyVersion(), nil
+	}
+
+	switch o {
+	case "", "=":
+		return rangeOf(p.lower(), p.upper()), nil
+	case "^":
+		return rangeOf(p.lower(), p.caretUpper()), nil
+	case "~", "~>":
+		return rangeOf(p.lower(), p.upper()), nil
+	case ">=":
+		return comparatorSet{{op: opGE, sv: p.lower()}}, nil
+	case ">":
+		return comparatorSet{{op: opGE, sv: p.upper()}}, nil
+	case "<":
+		return comparatorSet{{op: opLT, sv: p.lower()}}, nil
+	case "<=":
+		return comparatorSet{{op: opLT, sv: p.upper()}}, nil
+	}
+
+	return nil, fmt.Errorf("the operator %q needs a complete %s, not %q",
+		o, Name, vsn)
+}
+
+// opFromString returns the comparison operator corresponding to the
+// string. The empty string is taken as the equality operator. It must only
+// be called with a comparison operator or the empty string.
+func opFromString(s string) op {
+	for _, os := range opStrings {
+		if os.s == s {
+			return os.op
+		}
+	}
+
+	return opEQ
+}
+
+// desugarHyphenRange converts the hyphen range "from - to" into the
+// equivalent primitive comparators. A partial lower bound is filled out
+// with zeros and a partial upper bound matches any version having the
+// version numbers given.
+func desugarHyphenRange(from, to string) (comparatorSet, error) {
+	lo, err := parsePartialSV(from)
+	if err != nil {
+		return nil, err
+	}
+
+	hi, err := parsePartialSV(to)
+	if err != nil {
+		return nil, err
+	}
+
+	cs := comparatorSet{}
+
+	if lo.count > 0 {
+		cs = append(cs, comparator{op: opGE, sv: lo.lower()})
+	}
+
+	switch hi.count {
+	case 0:
+	case semverVsnPartCount:
+		cs = append(cs, comparator{op: opLE, sv: hi.sv})
+	default:
+		cs = append(cs, comparator{op: opLT, sv: hi.upper()})
+	}
+
+	if len(cs) == 0 {
+		return anyVersion(), nil
+	}
+
+	return cs, nil
+}
@@ -0,0 +1,675 @@
+package semver_test
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/nickwells/semver.mod/v3/semver"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestParseConstraint(t *testing.T) {
+	const badConstraint = "bad " + semver.ConstraintName
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		cStr   string
+		expStr string
+	}{
+		{
+			ID:     testhelper.MkID("good - single version, no operator"),
+			cStr:   "1.2.3",
+			expStr: "=1.2.3",
+		},
+		{
+			ID:     testhelper.MkID("good - single version with 'v'"),
+			cStr:   "v1.2.3",
+			expStr: "=1.2.3",
+		},
+		{
+			ID:     testhelper.MkID("good - all operators"),
+			cStr:   "=1.0.0 !=1.0.1 <1.0.2 <=1.0.3 >1.0.4 >=1.0.5",
+			expStr: "=1.0.0 !=1.0.1 <1.0.2 <=1.0.3 >1.0.4 >=1.0.5",
+		},
+		{
+			ID:     testhelper.MkID("good - operators separated by space"),
+			cStr:   ">= 1.2.0   < 2.0.0",
+			expStr: ">=1.2.0 <2.0.0",
+		},
+		{
+			ID:     testhelper.MkID("good - alternatives"),
+			cStr:   ">=1.2.0 <2.0.0||>=3.0.0-rc.1+build",
+			expStr: ">=1.2.0 <2.0.0 || >=3.0.0-rc.1+build",
+		},
+		{
+			ID:     testhelper.MkID("bad - empty"),
+			cStr:   "",
+			ExpErr: testhelper.MkExpErr(badConstraint, "an alternative is empty"),
+		},
+		{
+			ID:     testhelper.MkID("bad - empty alternative"),
+			cStr:   ">=1.2.0 || ",
+			ExpErr: testhelper.MkExpErr(badConstraint, "an alternative is empty"),
+		},
+		{
+			ID:   testhelper.MkID("bad - operator with no version"),
+			cStr: ">=1.2.0 <",
+			ExpErr: testhelper.MkExpErr(badConstraint,
+				`the operator "<" has no version`),
+		},
+		{
+			ID:   testhelper.MkID("bad - bad version"),
+			cStr: ">=1.2.3.4",
+			ExpErr: testhelper.MkExpErr(badConstraint,
+				`the semantic version ID: "1.2.3.4" has too many parts`),
+		},
+		{
+			ID:   testhelper.MkID("bad - number after a wildcard"),
+			cStr: "1.x.3",
+			ExpErr: testhelper.MkExpErr(badConstraint,
+				`the semantic version ID: "1.x.3" has a number after a wildcard`),
+		},
+		{
+			ID:   testhelper.MkID("bad - partial version with a pre-release"),
+			cStr: "^1.2-beta",
+			ExpErr: testhelper.MkExpErr(badConstraint,
+				`the semantic version ID: "1.2-beta" is incomplete`),
+		},
+		{
+			ID:   testhelper.MkID("bad - not-equal with a partial version"),
+			cStr: "!=1.2",
+			ExpErr: testhelper.MkExpErr(badConstraint,
+				`the operator "!=" needs a complete semantic version ID`),
+		},
+		{
+			ID:   testhelper.MkID("bad - hyphen range with no upper bound"),
+			cStr: "1.2.3 -",
+			ExpErr: testhelper.MkExpErr(badConstraint,
+				`the hyphen range starting "1.2.3" has no upper bound`),
+		},
+		{
+			ID:   testhelper.MkID("bad - hyphen range with no lower bound"),
+			cStr: "- 1.2.3",
+			ExpErr: testhelper.MkExpErr(badConstraint,
+				`the hyphen range ending "1.2.3" has no lower bound`),
+		},
+		{
+			ID:   testhelper.MkID("bad - hyphen alone"),
+			cStr: "1.0.0 || -",
+			ExpErr: testhelper.MkExpErr(badConstraint,
+				"a hyphen range has no bounds"),
+		},
+		{
+			ID:   testhelper.MkID("bad - hyphen range with two separators"),
+			cStr: "1.2.3 - - 2.0.0",
+			ExpErr: testhelper.MkExpErr(badConstraint,
+				`the hyphen range starting "1.2.3" has no upper bound`),
+		},
+		{
+			ID:   testhelper.MkID("bad - hyphen range with a bad bound"),
+			cStr: "1.2.3 - 2.01",
+			ExpErr: testhelper.MkExpErr(badConstraint,
+				`the minor version: "01" has a leading 0`),
+		},
+		{
+			ID:   testhelper.MkID("bad - bad operator"),
+			cStr: "=>1.2.0",
+			ExpErr: testhelper.MkExpErr(badConstraint,
+				`the major version: ">1" is not an integer`),
+		},
+	}
+
+	for _, tc := range testCases {
+		c, err := semver.ParseConstraint(tc.cStr)
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			testhelper.DiffString(t, tc.IDStr(), "constraint string",
+				c.String(), tc.expStr)
+		}
+	}
+}
+
+func TestConstraintCheck(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		cStr     string
+		svStr    string
+		expMatch bool
+	}{
+		{
+			ID:       testhelper.MkID("equal - match"),
+			cStr:     "1.2.3",
+			svStr:    "v1.2.3",
+			expMatch: true,
+		},
+		{
+			ID:       testhelper.MkID("equal - build IDs are ignored"),
+			cStr:     "=1.2.3+x",
+			svStr:    "v1.2.3+y",
+			expMatch: true,
+		},
+		{
+			ID:    testhelper.MkID("equal - no match"),
+			cStr:  "=1.2.3",
+			svStr: "v1.2.4",
+		},
+		{
+			ID:    testhelper.MkID("not equal - no match"),
+			cStr:  "!=1.2.3",
+			svStr: "v1.2.3",
+		},
+		{
+			ID:       testhelper.MkID("not equal - match"),
+			cStr:     "!=1.2.3",
+			svStr:    "v1.2.4",
+			expMatch: true,
+		},
+		{
+			ID:       testhelper.MkID("range - match"),
+			cStr:     ">=1.2.0 <2.0.0",
+			svStr:    "v1.4.2",
+			expMatch: true,
+		},
+		{
+			ID:       testhelper.MkID("range - match at the lower bound"),
+			cStr:     ">=1.2.0 <2.0.0",
+			svStr:    "v1.2.0",
+			expMatch: true,
+		},
+		{
+			ID:    testhelper.MkID("range - no match at the upper bound"),
+			cStr:  ">=1.2.0 <2.0.0",
+			svStr: "v2.0.0",
+		},
+		{
+			ID:       testhelper.MkID("range - match at the inclusive bound"),
+			cStr:     ">1.2.0 <=2.0.0",
+			svStr:    "v2.0.0",
+			expMatch: true,
+		},
+		{
+			ID:    testhelper.MkID("range - no match at the exclusive bound"),
+			cStr:  ">1.2.0 <=2.0.0",
+			svStr: "v1.2.0",
+		},
+		{
+			ID:    testhelper.MkID("range - pre-release is excluded"),
+			cStr:  ">=1.2.0 <2.0.0",
+			svStr: "v1.4.2-rc.1",
+		},
+		{
+			ID:       testhelper.MkID("range - pre-release is named"),
+			cStr:     ">=1.4.2-rc.0 <2.0.0",
+			svStr:    "v1.4.2-rc.1",
+			expMatch: true,
+		},
+		{
+			ID:    testhelper.MkID("range - pre-release of another version"),
+			cStr:  ">=1.4.1-rc.0 <2.0.0",
+			svStr: "v1.4.2-rc.1",
+		},
+		{
+			ID:    testhelper.MkID("range - pre-release is below the range"),
+			cStr:  ">=1.4.2-rc.2 <2.0.0",
+			svStr: "v1.4.2-rc.1",
+		},
+		{
+			ID:       testhelper.MkID("range - release with pre-release bound"),
+			cStr:     ">=1.4.2-rc.2 <2.0.0",
+			svStr:    "v1.5.0",
+			expMatch: true,
+		},
+		{
+			ID:       testhelper.MkID("alternatives - first matches"),
+			cStr:     "<1.0.0 || >=3.0.0",
+			svStr:    "v0.9.0",
+			expMatch: true,
+		},
+		{
+			ID:       testhelper.MkID("alternatives - second matches"),
+			cStr:     "<1.0.0 || >=3.0.0",
+			svStr:    "v3.1.0",
+			expMatch: true,
+		},
+		{
+			ID:    testhelper.MkID("alternatives - neither matches"),
+			cStr:  "<1.0.0 || >=3.0.0",
+			svStr: "v2.1.0",
+		},
+		{
+			ID:       testhelper.MkID("alternatives - pre-release in one alt"),
+			cStr:     "<1.0.0 || >=3.0.0-beta",
+			svStr:    "v3.0.0-rc.1",
+			expMatch: true,
+		},
+		{
+			ID:    testhelper.MkID("alternatives - pre-release in other alt"),
+			cStr:  ">=3.0.0 || =2.0.0-beta",
+			svStr: "v3.0.0-rc.1",
+		},
+		{
+			ID:       testhelper.MkID("caret - pre-release is named"),
+			cStr:     "^1.2.3-beta.2",
+			svStr:    "v1.2.3-beta.3",
+			expMatch: true,
+		},
+		{
+			ID:    testhelper.MkID("caret - pre-release of another version"),
+			cStr:  "^1.2.3-beta.2",
+			svStr: "v1.2.4-beta.3",
+		},
+		{
+			ID:    testhelper.MkID("caret - 0.x excludes the next minor"),
+			cStr:  "^0.2.3",
+			svStr: "v0.3.0",
+		},
+		{
+			ID:       testhelper.MkID("x-range - match"),
+			cStr:     "1.2.x",
+			svStr:    "v1.2.9",
+			expMatch: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		c := semver.ParseConstraintOrPanic(tc.cStr)
+
+		sv, err := semver.ParseSV(tc.svStr)
+		if err != nil {
+			t.Fatal("Couldn't parse the semver: ", err)
+		}
+
+		testhelper.DiffBool(t, tc.IDStr(),
+			"does "+tc.svStr+" satisfy "+tc.cStr,
+			c.Check(sv), tc.expMatch)
+	}
+}
+
+func TestConstraintSugar(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		cStr   string
+		expStr string
+	}{
+		{
+			ID:     testhelper.MkID("caret"),
+			cStr:   "^1.2.3",
+			expStr: ">=1.2.3 <2.0.0",
+		},
+		{
+			ID:     testhelper.MkID("caret 0.x"),
+			cStr:   "^0.2.3",
+			expStr: ">=0.2.3 <0.3.0",
+		},
+		{
+			ID:     testhelper.MkID("caret 0.0.x"),
+			cStr:   "^0.0.3",
+			expStr: ">=0.0.3 <0.0.4",
+		},
+		{
+			ID:     testhelper.MkID("caret with pre-release"),
+			cStr:   "^1.2.3-beta.2",
+			expStr: ">=1.2.3-beta.2 <2.0.0",
+		},
+		{
+			ID:     testhelper.MkID("caret 1.2.x"),
+			cStr:   "^1.2.x",
+			expStr: ">=1.2.0 <2.0.0",
+		},
+		{
+			ID:     testhelper.MkID("caret 0.0.x"),
+			cStr:   "^0.0.x",
+			expStr: ">=0.0.0 <0.1.0",
+		},
+		{
+			ID:     testhelper.MkID("caret 0.0"),
+			cStr:   "^0.0",
+			expStr: ">=0.0.0 <0.1.0",
+		},
+		{
+			ID:     testhelper.MkID("caret 1.x"),
+			cStr:   "^1.x",
+			expStr: ">=1.0.0 <2.0.0",
+		},
+		{
+			ID:     testhelper.MkID("caret 0.x"),
+			cStr:   "^0.x",
+			expStr: ">=0.0.0 <1.0.0",
+		},
+		{
+			ID:     testhelper.MkID("caret *"),
+			cStr:   "^*",
+			expStr: ">=0.0.0",
+		},
+		{
+			ID:     testhelper.MkID("tilde"),
+			cStr:   "~1.2.3",
+			expStr: ">=1.2.3 <1.3.0",
+		},
+		{
+			ID:     testhelper.MkID("tilde 1.2"),
+			cStr:   "~1.2",
+			expStr: ">=1.2.0 <1.3.0",
+		},
+		{
+			ID:     testhelper.MkID("tilde 1"),
+			cStr:   "~1",
+			expStr: ">=1.0.0 <2.0.0",
+		},
+		{
+			ID:     testhelper.MkID("tilde 0.2.3"),
+			cStr:   "~0.2.3",
+			expStr: ">=0.2.3 <0.3.0",
+		},
+		{
+			ID:     testhelper.MkID("tilde with pre-release"),
+			cStr:   "~1.2.3-beta.2",
+			expStr: ">=1.2.3-beta.2 <1.3.0",
+		},
+		{
+			ID:     testhelper.MkID("tilde (~>)"),
+			cStr:   "~>1.2.3",
+			expStr: ">=1.2.3 <1.3.0",
+		},
+		{
+			ID:     testhelper.MkID("x-range *"),
+			cStr:   "*",
+			expStr: ">=0.0.0",
+		},
+		{
+			ID:     testhelper.MkID("x-range x"),
+			cStr:   "x",
+			expStr: ">=0.0.0",
+		},
+		{
+			ID:     testhelper.MkID("x-range 1.x"),
+			cStr:   "1.x",
+			expStr: ">=1.0.0 <2.0.0",
+		},
+		{
+			ID:     testhelper.MkID("x-range 1.2.x"),
+			cStr:   "1.2.x",
+			expStr: ">=1.2.0 <1.3.0",
+		},
+		{
+			ID:     testhelper.MkID("x-range 1.2.*"),
+			cStr:   "1.2.*",
+			expStr: ">=1.2.0 <1.3.0",
+		},
+		{
+			ID:     testhelper.MkID("x-range 1.X"),
+			cStr:   "1.X",
+			expStr: ">=1.0.0 <2.0.0",
+		},
+		{
+			ID:     testhelper.MkID("partial 1.2"),
+			cStr:   "1.2",
+			expStr: ">=1.2.0 <1.3.0",
+		},
+		{
+			ID:     testhelper.MkID("partial =1"),
+			cStr:   "=1",
+			expStr: ">=1.0.0 <2.0.0",
+		},
+		{
+			ID:     testhelper.MkID("partial v1.2"),
+			cStr:   "v1.2",
+			expStr: ">=1.2.0 <1.3.0",
+		},
+		{
+			ID:     testhelper.MkID("partial >=1.2"),
+			cStr:   ">=1.2",
+			expStr: ">=1.2.0",
+		},
+		{
+			ID:     testhelper.MkID("partial >1.2"),
+			cStr:   ">1.2",
+			expStr: ">=1.3.0",
+		},
+		{
+			ID:     testhelper.MkID("partial >1"),
+			cStr:   ">1",
+			expStr: ">=2.0.0",
+		},
+		{
+			ID:     testhelper.MkID("partial <1.2"),
+			cStr:   "<1.2",
+			expStr: "<1.2.0",
+		},
+		{
+			ID:     testhelper.MkID("partial <=1.2"),
+			cStr:   "<=1.2",
+			expStr: "<1.3.0",
+		},
+		{
+			ID:     testhelper.MkID("partial <=1"),
+			cStr:   "<=1",
+			expStr: "<2.0.0",
+		},
+		{
+			ID:     testhelper.MkID("partial >*"),
+			cStr:   ">*",
+			expStr: "<0.0.0",
+		},
+		{
+			ID:     testhelper.MkID("partial <=*"),
+			cStr:   "<=*",
+			expStr: ">=0.0.0",
+		},
+		{
+			ID:     testhelper.MkID("hyphen"),
+			cStr:   "1.2.3 - 2.3.4",
+			expStr: ">=1.2.3 <=2.3.4",
+		},
+		{
+			ID:     testhelper.MkID("hyphen - partial lower"),
+			cStr:   "1.2 - 2.3.4",
+			expStr: ">=1.2.0 <=2.3.4",
+		},
+		{
+			ID:     testhelper.MkID("hyphen - partial upper"),
+			cStr:   "1.2.3 - 2.3",
+			expStr: ">=1.2.3 <2.4.0",
+		},
+		{
+			ID:     testhelper.MkID("hyphen - partial upper (major only)"),
+			cStr:   "1.2.3 - 2",
+			expStr: ">=1.2.3 <3.0.0",
+		},
+		{
+			ID:     testhelper.MkID("hyphen - wildcard lower"),
+			cStr:   "* - 2.3.4",
+			expStr: "<=2.3.4",
+		},
+		{
+			ID:     testhelper.MkID("hyphen - in an alternative"),
+			cStr:   "1.2.3 - 2.3.4 !=2.0.0 || ^3.1",
+			expStr: ">=1.2.3 <=2.3.4 !=2.0.0 || >=3.1.0 <4.0.0",
+		},
+		{
+			ID:     testhelper.MkID("operators separated by space"),
+			cStr:   "^ 1.2.3 || ~ 2",
+			expStr: ">=1.2.3 <2.0.0 || >=2.0.0 <3.0.0",
+		},
+	}
+
+	for _, tc := range testCases {
+		c, err := semver.ParseConstraint(tc.cStr)
+		if err != nil {
+			t.Log(tc.IDStr())
+			t.Errorf("\t: unexpected error: %s", err)
+
+			continue
+		}
+
+		testhelper.DiffString(t, tc.IDStr(), "desugared constraint",
+			c.String(), tc.expStr)
+	}
+}
+
+// tabFields splits the line into its tab-separated fields, ignoring any
+// empty fields
+func tabFields(line string) []string {
+	return strings.FieldsFunc(line, func(r rune) bool { return r == '\t' })
+}
+
+func TestCargoReqDesugar(t *testing.T) {
+	const fname = "testdata/cargoReqs"
+
+	file, err := os.Open(fname)
+	if err != nil {
+		t.Fatal("Cannot open the test file: ", fname, " - ", err)
+	}
+	defer file.Close()
+
+	lineNum := 0
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lineNum++
+
+		parts := tabFields(scanner.Text())
+		if len(parts) != 2 {
+			continue
+		}
+
+		reqStr, expStr := parts[0], parts[1]
+
+		c, err := semver.ParseCargoReq(reqStr)
+		if err != nil {
+			t.Logf("parsing: %s:%d : %s", fname, lineNum, reqStr)
+			t.Errorf("\t: unexpected error: %s", err)
+
+			continue
+		}
+
+		testhelper.DiffString(t,
+			fmt.Sprintf("%s:%d", fname, lineNum), "desugared requirement",
+			c.String(), expStr)
+	}
+}
+
+func TestCargoReqMatches(t *testing.T) {
+	const fname = "testdata/cargoReqMatches"
+
+	file, err := os.Open(fname)
+	if err != nil {
+		t.Fatal("Cannot open the test file: ", fname, " - ", err)
+	}
+	defer file.Close()
+
+	lineNum := 0
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lineNum++
+
+		parts := tabFields(scanner.Text())
+		if len(parts) != 3 {
+			continue
+		}
+
+		reqStr, svStr, expMatch := parts[0], parts[1], parts[2] == "yes"
+
+		c, err := semver.ParseCargoReq(reqStr)
+		if err != nil {
+			t.Logf("parsing: %s:%d : %s", fname, lineNum, reqStr)
+			t.Errorf("\t: unexpected error: %s", err)
+
+			continue
+		}
+
+		sv, err := semver.ParseStrictSV(svStr)
+		if err != nil {
+			t.Logf("parsing: %s:%d : %s", fname, lineNum, svStr)
+			t.Errorf("\t: unexpected error: %s", err)
+
+			continue
+		}
+
+		testhelper.DiffBool(t,
+			fmt.Sprintf("%s:%d", fname, lineNum),
+			"does "+svStr+" satisfy "+reqStr,
+			c.Check(sv), expMatch)
+	}
+}
+
+func TestParseCargoReq(t *testing.T) {
+	const badConstraint = "bad " + semver.ConstraintName
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		reqStr string
+	}{
+		{
+			ID:     testhelper.MkID("good"),
+			reqStr: ">=1.2.0, <2",
+		},
+		{
+			ID:     testhelper.MkID("bad - empty"),
+			ExpErr: testhelper.MkExpErr(badConstraint, "a clause is empty"),
+		},
+		{
+			ID:     testhelper.MkID("bad - empty clause"),
+			reqStr: ">=1.2.0,",
+			ExpErr: testhelper.MkExpErr(badConstraint, "a clause is empty"),
+		},
+		{
+			ID:     testhelper.MkID("bad - not-equal operator"),
+			reqStr: "!=1.2.0",
+			ExpErr: testhelper.MkExpErr(badConstraint,
+				`the operator "!=" is not allowed`),
+		},
+		{
+			ID:     testhelper.MkID("bad - alternatives"),
+			reqStr: "1.2.0 || 2.0.0",
+			ExpErr: testhelper.MkExpErr(badConstraint,
+				`the clause "1.2.0 || 2.0.0" has more than one version`),
+		},
+		{
+			ID:     testhelper.MkID("bad - clauses separated by space"),
+			reqStr: ">=1.2.0 <2.0.0",
+			ExpErr: testhelper.MkExpErr(badConstraint,
+				`the clause ">=1.2.0 <2.0.0" has more than one version`),
+		},
+		{
+			ID:     testhelper.MkID("bad - operator with a wildcard"),
+			reqStr: ">=1.*",
+			ExpErr: testhelper.MkExpErr(badConstraint,
+				`the clause ">=1.*" has both an operator and a wildcard`),
+		},
+		{
+			ID:     testhelper.MkID("bad - tilde with a wildcard"),
+			reqStr: "~1.2.*",
+			ExpErr: testhelper.MkExpErr(badConstraint,
+				`the clause "~1.2.*" has both an operator and a wildcard`),
+		},
+		{
+			ID:     testhelper.MkID("bad - caret with a wildcard"),
+			reqStr: ">=1.0.0, ^*",
+			ExpErr: testhelper.MkExpErr(badConstraint,
+				`the clause "^*" has both an operator and a wildcard`),
+		},
+		{
+			ID:     testhelper.MkID("bad - equals with a spaced wildcard"),
+			reqStr: "= 1.x",
+			ExpErr: testhelper.MkExpErr(badConstraint,
+				`the clause "= 1.x" has both an operator and a wildcard`),
+		},
+		{
+			ID:     testhelper.MkID("bad - operator with no version"),
+			reqStr: ">=1.2.0, <",
+			ExpErr: testhelper.MkExpErr(badConstraint,
+				`the operator "<" has no version`),
+		},
+	}
+
+	for _, tc := range testCases {
+		_, err := semver.ParseCargoReq(tc.reqStr)
+		testhelper.CheckExpErr(t, err, tc)
+	}
+}
@@ -0,0 +1,163 @@
+package convcommit
+
+import (
+	"fmt"
+
+	"github.com/nickwells/semver.mod/v3/semver"
+)
+
+// Bump is the part of a version to be incremented
+type Bump int
+
+// These are the possible bumps in increasing order of size
+const (
+	BumpNone Bump = iota
+	BumpPatch
+	BumpMinor
+	BumpMajor
+)
+
+// String returns the name of the Bump
+func (b Bump) String() string {
+	switch b {
+	case BumpNone:
+		return "none"
+	case BumpPatch:
+		return "patch"
+	case BumpMinor:
+		return "minor"
+	case BumpMajor:
+		return "major"
+	}
+
+	return fmt.Sprintf("Bump(%d)", int(b))
+}
+
+// Bump returns the part of the version which the commit requires to be
+// incremented: major for a breaking change, minor for a new feature
+// ("feat"), patch for a bug fix ("fix") and none for anything else
+func (c Commit) Bump() Bump {
+	switch {
+	case c.Breaking:
+		return BumpMajor
+	case c.Type == TypeFeat:
+		return BumpMinor
+	case c.Type == TypeFix:
+		return BumpPatch
+	}
+
+	return BumpNone
+}
+
+// Reason records a commit which requires the version to be bumped
+type Reason struct {
+	// Commit is the commit requiring the bump
+	Commit *Commit
+	// Bump is the bump that the commit requires. For a version with a
+	// major version number of 0 this is minor for a breaking change.
+	Bump Bump
+	// Why describes why the commit requires the bump
+	Why string
+}
+
+// String returns a description of the Reason
+func (r Reason) String() string {
+	return fmt.Sprintf("%s (%s): %s", r.Bump, r.Why, r.Commit.Header)
+}
+
+// reasonFor returns the Reason that the commit requires the version to be
+// bumped. The boolean return value is false if no bump is needed.
+func reasonFor(c *Commit, initialDevelopment bool) (Reason, bool) {
+	r := Reason{Commit: c, Bump: c.Bump()}
+
+	switch r.Bump {
+	case BumpMajor:
+		r.Why = "a breaking change"
+
+		if initialDevelopment {
+			r.Bump = BumpMinor
+			r.Why += " in initial development (major version 0)"
+		}
+	case BumpMinor:
+		r.Why = "a new feature"
+	case BumpPatch:
+		r.Why = "a bug fix"
+	case BumpNone:
+		return r, false
+	}
+
+	return r, true
+}
+
+// preReleaseBump returns the bump which the pre-release version has
+// already made: a pre-release of vX.Y.Z with a non-zero patch number
+// follows a patch bump, one of vX.Y.0 follows a minor bump and one of
+// vX.0.0 follows a major bump
+func preReleaseBump(sv *semver.SV) Bump {
+	switch {
+	case sv.PatchBig().Sign() != 0:
+		return BumpPatch
+	case sv.MinorBig().Sign() != 0:
+		return BumpMinor
+	}
+
+	return BumpMajor
+}
+
+// Recommend returns the version that should follow the current version
+// given the commits made since it was released, together with the reasons
+// for each commit which requires the version to be bumped. The largest bump
+// required is applied using the IncrMajor, IncrMinor or IncrPatch method
+// and the build IDs are removed.
+//
+// While the major version number is 0 (the initial development phase) a
+// breaking change increments the minor version number rather than the
+// major.
+//
+// If the current version is a pre-release the bump to its major, minor and
+// patch numbers has already been made. So if the bump required is no
+// larger than that, the version returned is the release of the current
+// version: v1.4.0-rc.1 followed by a fix or a feature gives v1.4.0 but
+// followed by a breaking change it gives v2.0.0.
+//
+// If no commit requires a bump the version returned is a copy of the
+// current version and there are no reasons. A nil current version is
+// taken to be v0.0.0. The current version is not changed.
+func Recommend(current *semver.SV, commits []*Commit) (*semver.SV, []Reason) {
+	next := semver.NewSVOrPanic(0, 0, 0, nil, nil)
+	if current != nil {
+		next = current.Clone()
+	}
+
+	initialDevelopment := next.MajorBig().Sign() == 0
+
+	reasons := []Reason{}
+	bump := BumpNone
+
+	for _, c := range commits {
+		r, ok := reasonFor(c, initialDevelopment)
+		if !ok {
+			continue
+		}
+
+		reasons = append(reasons, r)
+		bump = max(bump, r.Bump)
+	}
+
+	switch {
+	case bump == BumpNone:
+		return next, reasons
+	case next.HasPreRelIDs() && bump <= preReleaseBump(next):
+		next.ClearPreRelIDs()
+	case bump == BumpMajor:
+		next.IncrMajor()
+	case bump == BumpMinor:
+		next.IncrMinor()
+	case bump == BumpPatch:
+		next.IncrPatch()
+	}
+
+	next.ClearBuildIDs()
+
+	return next, reasons
+}
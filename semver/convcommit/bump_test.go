@@ -0,0 +1,246 @@
+package convcommit_test
+
+import (
+	"testing"
+
+	"github.com/nickwells/semver.mod/v3/semver"
+	"github.com/nickwells/semver.mod/v3/semver/convcommit"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+// mkCommits parses the messages, failing the test if any is bad
+func mkCommits(t *testing.T, msgs ...string) []*convcommit.Commit {
+	t.Helper()
+
+	commits := []*convcommit.Commit{}
+
+	for _, msg := range msgs {
+		c, err := convcommit.Parse(msg)
+		if err != nil {
+			t.Fatal("unexpected error: ", err)
+		}
+
+		commits = append(commits, c)
+	}
+
+	return commits
+}
+
+func TestRecommend(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		current    string
+		msgs       []string
+		expNext    string
+		expReasons []string
+	}{
+		{
+			ID:         testhelper.MkID("no commits"),
+			current:    "v1.2.3",
+			expNext:    "v1.2.3",
+			expReasons: []string{},
+		},
+		{
+			ID:         testhelper.MkID("no bump needed"),
+			current:    "v1.2.3+b.7",
+			msgs:       []string{"docs: fix a typo", "chore: tidy"},
+			expNext:    "v1.2.3+b.7",
+			expReasons: []string{},
+		},
+		{
+			ID:      testhelper.MkID("fix"),
+			current: "v1.2.3",
+			msgs:    []string{"docs: fix a typo", "fix: off by one"},
+			expNext: "v1.2.4",
+			expReasons: []string{
+				"patch (a bug fix): fix: off by one",
+			},
+		},
+		{
+			ID:      testhelper.MkID("feat and fix"),
+			current: "v1.2.3+b.7",
+			msgs: []string{
+				"fix: off by one",
+				"feat(cli): add a bump subcommand",
+			},
+			expNext: "v1.3.0",
+			expReasons: []string{
+				"patch (a bug fix): fix: off by one",
+				"minor (a new feature): feat(cli): add a bump subcommand",
+			},
+		},
+		{
+			ID:      testhelper.MkID("breaking, '!'"),
+			current: "v1.2.3",
+			msgs:    []string{"feat: a", "fix!: b"},
+			expNext: "v2.0.0",
+			expReasons: []string{
+				"minor (a new feature): feat: a",
+				"major (a breaking change): fix!: b",
+			},
+		},
+		{
+			ID:      testhelper.MkID("breaking, footer"),
+			current: "v1.2.3-rc.1",
+			msgs: []string{
+				"refactor: c\n\nBREAKING CHANGE: the API has changed",
+			},
+			expNext: "v2.0.0",
+			expReasons: []string{
+				"major (a breaking change): refactor: c",
+			},
+		},
+		{
+			ID:      testhelper.MkID("pre-release, fix"),
+			current: "v1.4.0-rc.1+b.7",
+			msgs:    []string{"fix: a"},
+			expNext: "v1.4.0",
+			expReasons: []string{
+				"patch (a bug fix): fix: a",
+			},
+		},
+		{
+			ID:      testhelper.MkID("pre-release, feat"),
+			current: "v1.4.0-rc.1",
+			msgs:    []string{"fix: a", "feat: b"},
+			expNext: "v1.4.0",
+			expReasons: []string{
+				"patch (a bug fix): fix: a",
+				"minor (a new feature): feat: b",
+			},
+		},
+		{
+			ID:      testhelper.MkID("pre-release of a patch, feat"),
+			current: "v1.4.1-rc.1",
+			msgs:    []string{"feat: b"},
+			expNext: "v1.5.0",
+			expReasons: []string{
+				"minor (a new feature): feat: b",
+			},
+		},
+		{
+			ID:      testhelper.MkID("pre-release of a major, breaking"),
+			current: "v2.0.0-beta.3",
+			msgs:    []string{"feat!: c"},
+			expNext: "v2.0.0",
+			expReasons: []string{
+				"major (a breaking change): feat!: c",
+			},
+		},
+		{
+			ID:         testhelper.MkID("pre-release, no bump needed"),
+			current:    "v1.4.0-rc.1",
+			msgs:       []string{"docs: d"},
+			expNext:    "v1.4.0-rc.1",
+			expReasons: []string{},
+		},
+		{
+			ID:      testhelper.MkID("initial development, pre-release"),
+			current: "v0.5.0-rc.1",
+			msgs:    []string{"feat!: b"},
+			expNext: "v0.5.0",
+			expReasons: []string{
+				"minor (a breaking change in initial development" +
+					" (major version 0)): feat!: b",
+			},
+		},
+		{
+			ID:      testhelper.MkID("initial development, breaking"),
+			current: "v0.4.1",
+			msgs:    []string{"fix: a", "feat!: b"},
+			expNext: "v0.5.0",
+			expReasons: []string{
+				"patch (a bug fix): fix: a",
+				"minor (a breaking change in initial development" +
+					" (major version 0)): feat!: b",
+			},
+		},
+		{
+			ID:      testhelper.MkID("initial development, fix"),
+			current: "v0.4.1",
+			msgs:    []string{"fix: a"},
+			expNext: "v0.4.2",
+			expReasons: []string{
+				"patch (a bug fix): fix: a",
+			},
+		},
+		{
+			ID:      testhelper.MkID("no current version"),
+			msgs:    []string{"feat: a"},
+			expNext: "v0.1.0",
+			expReasons: []string{
+				"minor (a new feature): feat: a",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		var current *semver.SV
+
+		if tc.current != "" {
+			var err error
+
+			current, err = semver.ParseSV(tc.current)
+			if err != nil {
+				t.Fatal(tc.IDStr(), ": unexpected error: ", err)
+			}
+		}
+
+		next, reasons := convcommit.Recommend(current,
+			mkCommits(t, tc.msgs...))
+		testhelper.DiffString(t, tc.IDStr(), "next version",
+			next.String(), tc.expNext)
+
+		reasonStrs := []string{}
+		for _, r := range reasons {
+			reasonStrs = append(reasonStrs, r.String())
+		}
+
+		testhelper.DiffStringSlice(t, tc.IDStr(), "reasons",
+			reasonStrs, tc.expReasons)
+
+		if current != nil {
+			testhelper.DiffString(t, tc.IDStr(), "current version",
+				current.String(), tc.current)
+		}
+	}
+}
+
+func TestBumpString(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		b      convcommit.Bump
+		expStr string
+	}{
+		{
+			ID:     testhelper.MkID("none"),
+			b:      convcommit.BumpNone,
+			expStr: "none",
+		},
+		{
+			ID:     testhelper.MkID("patch"),
+			b:      convcommit.BumpPatch,
+			expStr: "patch",
+		},
+		{
+			ID:     testhelper.MkID("minor"),
+			b:      convcommit.BumpMinor,
+			expStr: "minor",
+		},
+		{
+			ID:     testhelper.MkID("major"),
+			b:      convcommit.BumpMajor,
+			expStr: "major",
+		},
+		{
+			ID:     testhelper.MkID("bad"),
+			b:      convcommit.Bump(9),
+			expStr: "Bump(9)",
+		},
+	}
+
+	for _, tc := range testCases {
+		testhelper.DiffString(t, tc.IDStr(), "string",
+			tc.b.String(), tc.expStr)
+	}
+}
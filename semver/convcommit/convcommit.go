@@ -0,0 +1,164 @@
+/*
+Package convcommit parses commit messages written according to the
+Conventional Commits specification (v1.0.0) and recommends the next
+semantic version from them. A commit message has a header of the form:
+
+	type(scope)!: description
+
+where the scope and the '!' are optional, then an optional body and then
+optional footers, each separated from what comes before by a blank line.
+A footer is of the form "token: value" or "token #value"; the token
+"BREAKING CHANGE" (or "BREAKING-CHANGE") marks a breaking change, as does
+a '!' in the header.
+*/
+package convcommit
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// These are the commit types with a meaning for the version
+const (
+	TypeFeat = "feat"
+	TypeFix  = "fix"
+)
+
+// These are the footer tokens marking a breaking change
+const (
+	BreakingChange       = "BREAKING CHANGE"
+	BreakingChangeHyphen = "BREAKING-CHANGE"
+)
+
+var (
+	headerRE = regexp.MustCompile(
+		`^([A-Za-z][A-Za-z0-9-]*)(?:\(([^()\r\n]*)\))?(!)?: (.*)$`)
+	footerRE = regexp.MustCompile(
+		`^(` + BreakingChange + `|[A-Za-z][A-Za-z0-9-]*)(?:: | #)(.*)$`)
+)
+
+// Footer is a footer of a commit message
+type Footer struct {
+	// Token is the footer token, such as "Reviewed-by" or "BREAKING CHANGE"
+	Token string
+	// Value is the value of the footer; it may span several lines
+	Value string
+}
+
+// Commit is a parsed Conventional Commit message
+type Commit struct {
+	// Header is the first line of the message
+	Header string
+	// Type is the commit type, such as "feat" or "fix", in lower case
+	Type string
+	// Scope is the scope given in brackets after the type, it may be empty
+	Scope string
+	// Description is the text of the header after the type and scope
+	Description string
+	// Body is the text between the header and the footers, it may be empty
+	Body string
+	// Footers holds the footers in the order given
+	Footers []Footer
+	// Breaking is true if the commit has a '!' in the header or a
+	// BREAKING CHANGE footer
+	Breaking bool
+}
+
+// Parse parses the commit message. It returns an error if the message does
+// not start with a header of the form "type(scope)!: description" or if
+// the header is not followed by a blank line.
+func Parse(msg string) (*Commit, error) {
+	c, err := parse(msg)
+	if err != nil {
+		header, _, _ := strings.Cut(msg, "\n")
+
+		return nil, fmt.Errorf("bad conventional commit: %q - %w", header, err)
+	}
+
+	return c, nil
+}
+
+// parse parses the commit message
+func parse(msg string) (*Commit, error) {
+	lines := strings.Split(
+		strings.TrimRight(strings.ReplaceAll(msg, "\r\n", "\n"), "\n"),
+		"\n")
+
+	parts := headerRE.FindStringSubmatch(lines[0])
+	if parts == nil {
+		return nil, errors.New(
+			"the header must be of the form: type(scope)!: description")
+	}
+
+	c := &Commit{
+		Header:      lines[0],
+		Type:        strings.ToLower(parts[1]),
+		Scope:       strings.TrimSpace(parts[2]),
+		Description: strings.TrimSpace(parts[4]),
+		Breaking:    parts[3] != "",
+	}
+
+	if c.Description == "" {
+		return nil, errors.New("the description is empty")
+	}
+
+	rest := lines[1:]
+	if len(rest) == 0 {
+		return c, nil
+	}
+
+	if strings.TrimSpace(rest[0]) != "" {
+		return nil, errors.New("the header must be followed by a blank line")
+	}
+
+	body, footers := splitFooters(rest)
+	c.Body = strings.TrimSpace(strings.Join(body, "\n"))
+	c.Footers = parseFooters(footers)
+
+	for _, f := range c.Footers {
+		if f.Token == BreakingChange || f.Token == BreakingChangeHyphen {
+			c.Breaking = true
+		}
+	}
+
+	return c, nil
+}
+
+// splitFooters splits the lines following the header into the body and the
+// footers. The footers start at the first line, following a blank line,
+// which starts with a footer token.
+func splitFooters(lines []string) ([]string, []string) {
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i-1]) == "" &&
+			footerRE.MatchString(lines[i]) {
+			return lines[:i], lines[i:]
+		}
+	}
+
+	return lines, nil
+}
+
+// parseFooters parses the footer lines. A line which does not start with
+// a footer token continues the value of the previous footer.
+func parseFooters(lines []string) []Footer {
+	footers := []Footer{}
+
+	for _, line := range lines {
+		if parts := footerRE.FindStringSubmatch(line); parts != nil {
+			footers = append(footers, Footer{Token: parts[1], Value: parts[2]})
+
+			continue
+		}
+
+		last := &footers[len(footers)-1]
+		last.Value += "\n" + line
+	}
+
+	for i := range footers {
+		footers[i].Value = strings.TrimSpace(footers[i].Value)
+	}
+
+	return footers
+}
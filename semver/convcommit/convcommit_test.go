@@ -0,0 +1,161 @@
+package convcommit_test
+
+import (
+	"testing"
+
+	"github.com/nickwells/semver.mod/v3/semver/convcommit"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+// footerStrs returns the footers as strings of the form "token=value"
+func footerStrs(footers []convcommit.Footer) []string {
+	strs := []string{}
+	for _, f := range footers {
+		strs = append(strs, f.Token+"="+f.Value)
+	}
+
+	return strs
+}
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		msg         string
+		expType     string
+		expScope    string
+		expDesc     string
+		expBody     string
+		expFooters  []string
+		expBreaking bool
+	}{
+		{
+			ID:         testhelper.MkID("header only"),
+			msg:        "fix: handle an empty list",
+			expType:    "fix",
+			expDesc:    "handle an empty list",
+			expFooters: []string{},
+		},
+		{
+			ID:         testhelper.MkID("header only, trailing newline"),
+			msg:        "feat(parser): add arrays\n",
+			expType:    "feat",
+			expScope:   "parser",
+			expDesc:    "add arrays",
+			expFooters: []string{},
+		},
+		{
+			ID:          testhelper.MkID("breaking, '!'"),
+			msg:         "feat(api)!: remove the v1 endpoints",
+			expType:     "feat",
+			expScope:    "api",
+			expDesc:     "remove the v1 endpoints",
+			expFooters:  []string{},
+			expBreaking: true,
+		},
+		{
+			ID:         testhelper.MkID("type in upper case"),
+			msg:        "FIX: handle an empty list",
+			expType:    "fix",
+			expDesc:    "handle an empty list",
+			expFooters: []string{},
+		},
+		{
+			ID: testhelper.MkID("body and footers"),
+			msg: "fix: prevent racing of requests\n" +
+				"\n" +
+				"Introduce a request id and a reference to latest request.\n" +
+				"\n" +
+				"Remove timeouts which were used to mitigate the racing" +
+				" issue.\n" +
+				"\n" +
+				"Reviewed-by: Z\n" +
+				"Refs #123\n",
+			expType: "fix",
+			expDesc: "prevent racing of requests",
+			expBody: "Introduce a request id and a reference to latest" +
+				" request.\n" +
+				"\n" +
+				"Remove timeouts which were used to mitigate the racing" +
+				" issue.",
+			expFooters: []string{"Reviewed-by=Z", "Refs=123"},
+		},
+		{
+			ID: testhelper.MkID("breaking change footer, multi-line"),
+			msg: "refactor: drop support for Node 6\n" +
+				"\n" +
+				"BREAKING CHANGE: refactor to use JavaScript features\n" +
+				"  not available in Node 6.\n",
+			expType: "refactor",
+			expDesc: "drop support for Node 6",
+			expFooters: []string{
+				"BREAKING CHANGE=refactor to use JavaScript features\n" +
+					"  not available in Node 6.",
+			},
+			expBreaking: true,
+		},
+		{
+			ID: testhelper.MkID("breaking change footer, hyphen"),
+			msg: "chore: update the build\r\n" +
+				"\r\n" +
+				"BREAKING-CHANGE: Go 1.26 is needed\r\n",
+			expType:     "chore",
+			expDesc:     "update the build",
+			expFooters:  []string{"BREAKING-CHANGE=Go 1.26 is needed"},
+			expBreaking: true,
+		},
+		{
+			ID: testhelper.MkID("breaking change in lower case is not"),
+			msg: "docs: explain\n" +
+				"\n" +
+				"breaking change: not really\n",
+			expType:    "docs",
+			expDesc:    "explain",
+			expBody:    "breaking change: not really",
+			expFooters: []string{},
+		},
+		{
+			ID:     testhelper.MkID("not conventional"),
+			msg:    "Merge branch 'main' into feature",
+			ExpErr: testhelper.MkExpErr("the header must be of the form"),
+		},
+		{
+			ID:     testhelper.MkID("no space after colon"),
+			msg:    "fix:tidy",
+			ExpErr: testhelper.MkExpErr("the header must be of the form"),
+		},
+		{
+			ID:     testhelper.MkID("empty description"),
+			msg:    "fix:  ",
+			ExpErr: testhelper.MkExpErr("the description is empty"),
+		},
+		{
+			ID:  testhelper.MkID("no blank line after header"),
+			msg: "fix: tidy\nmore text",
+			ExpErr: testhelper.MkExpErr(
+				`bad conventional commit: "fix: tidy"`,
+				"the header must be followed by a blank line"),
+		},
+		{
+			ID:     testhelper.MkID("empty"),
+			msg:    "",
+			ExpErr: testhelper.MkExpErr("the header must be of the form"),
+		},
+	}
+
+	for _, tc := range testCases {
+		c, err := convcommit.Parse(tc.msg)
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			testhelper.DiffString(t, tc.IDStr(), "type", c.Type, tc.expType)
+			testhelper.DiffString(t, tc.IDStr(), "scope",
+				c.Scope, tc.expScope)
+			testhelper.DiffString(t, tc.IDStr(), "description",
+				c.Description, tc.expDesc)
+			testhelper.DiffString(t, tc.IDStr(), "body", c.Body, tc.expBody)
+			testhelper.DiffStringSlice(t, tc.IDStr(), "footers",
+				footerStrs(c.Footers), tc.expFooters)
+			testhelper.DiffBool(t, tc.IDStr(), "breaking",
+				c.Breaking, tc.expBreaking)
+		}
+	}
+}
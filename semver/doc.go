@@ -9,6 +9,16 @@ object will validate the parts - the major, minor and patch numbers and the
 pre-release and build IDs and will return an error if any part is invalid.
 
 There is an SVList type (a slice of pointers to SVs) which has member
-functions (Len, Less and Swap) which make it able to be sorted.
+functions (Len, Less and Swap) which make it able to be sorted. It also
+has methods to find the highest and lowest versions in the list and to
+select those versions which satisfy a Constraint.
+
+A Constraint can be parsed from a string such as ">=1.2.0 <2.0.0 || >=3.0.0"
+and then used to check whether an SV satisfies it. Constraints can be
+written in the npm dialect (the default) or the Cargo dialect.
+
+A VersionSet can be made from a Constraint and then combined with other
+VersionSets (by intersection, union and complement) and checked for
+emptiness. This allows conflicting constraints to be detected.
 */
 package semver
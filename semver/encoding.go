@@ -0,0 +1,107 @@
+package semver
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+var jsonNull = []byte("null")
+
+// MarshalText returns the string form of the SV as a slice of bytes. An SV
+// which has not been set is returned as an empty slice. This satisfies the
+// encoding.TextMarshaler interface.
+func (sv SV) MarshalText() ([]byte, error) {
+	return []byte(sv.String()), nil
+}
+
+// UnmarshalText parses the text (see ParseSV) and sets the SV from it. If
+// the text is empty the SV is cleared and will not be marked as having been
+// set. This satisfies the encoding.TextUnmarshaler interface.
+func (sv *SV) UnmarshalText(text []byte) error {
+	return sv.unmarshalText(text, ParseSV)
+}
+
+// MarshalJSON returns the SV as a JSON string. An SV which has not been set
+// is returned as the JSON null value. This satisfies the json.Marshaler
+// interface.
+func (sv SV) MarshalJSON() ([]byte, error) {
+	if !sv.hasBeenSet {
+		return jsonNull, nil
+	}
+
+	return json.Marshal(sv.String())
+}
+
+// UnmarshalJSON parses the JSON string (see ParseSV) and sets the SV from
+// it. If the JSON value is null or the empty string the SV is cleared and
+// will not be marked as having been set. This satisfies the
+// json.Unmarshaler interface.
+//
+// Note that the json package will set a pointer to an SV to nil when the
+// JSON value is null without calling this method.
+func (sv *SV) UnmarshalJSON(data []byte) error {
+	return sv.unmarshalJSON(data, ParseSV)
+}
+
+// unmarshalText sets the SV from the text using the parse func
+func (sv *SV) unmarshalText(text []byte,
+	parse func(string) (*SV, error),
+) error {
+	if len(text) == 0 {
+		*sv = SV{}
+
+		return nil
+	}
+
+	newSV, err := parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	*sv = *newSV
+
+	return nil
+}
+
+// unmarshalJSON sets the SV from the JSON value using the parse func
+func (sv *SV) unmarshalJSON(data []byte,
+	parse func(string) (*SV, error),
+) error {
+	if bytes.Equal(bytes.TrimSpace(data), jsonNull) {
+		*sv = SV{}
+
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("bad %s - the JSON value must be a string: %w",
+			Name, err)
+	}
+
+	return sv.unmarshalText([]byte(s), parse)
+}
+
+// PrefixOptionalSV is an SV which, when it is unmarshalled from text or
+// JSON, will accept a version with or without the leading 'v'. This allows
+// it to read versions in the prefix-less form that ParseStrictSV takes as
+// well as the form that ParseSV takes. It is always marshalled with the
+// leading 'v'.
+type PrefixOptionalSV struct {
+	SV
+}
+
+// UnmarshalText parses the text, which may or may not start with a 'v', and
+// sets the SV from it. If the text is empty the SV is cleared and will not
+// be marked as having been set.
+func (posv *PrefixOptionalSV) UnmarshalText(text []byte) error {
+	return posv.unmarshalText(text, parseOptPfx)
+}
+
+// UnmarshalJSON parses the JSON string, which may or may not start with a
+// 'v', and sets the SV from it. If the JSON value is null or the empty
+// string the SV is cleared and will not be marked as having been set.
+func (posv *PrefixOptionalSV) UnmarshalJSON(data []byte) error {
+	return posv.unmarshalJSON(data, parseOptPfx)
+}
@@ -0,0 +1,218 @@
+package semver_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/nickwells/semver.mod/v3/semver"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestMarshal(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		sv      semver.SV
+		expText string
+		expJSON string
+	}{
+		{
+			ID:      testhelper.MkID("unset"),
+			expText: "",
+			expJSON: "null",
+		},
+		{
+			ID:      testhelper.MkID("release"),
+			sv:      *semver.NewSVOrPanic(1, 2, 3, nil, nil),
+			expText: "v1.2.3",
+			expJSON: `"v1.2.3"`,
+		},
+		{
+			ID: testhelper.MkID("pre-release and build IDs"),
+			sv: *semver.NewSVOrPanic(1, 2, 3,
+				[]string{"rc", "1"}, []string{"build", "7"}),
+			expText: "v1.2.3-rc.1+build.7",
+			expJSON: `"v1.2.3-rc.1+build.7"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		text, err := tc.sv.MarshalText()
+		if err != nil {
+			t.Log(tc.IDStr())
+			t.Errorf("\t: unexpected error from MarshalText(): %s", err)
+		} else {
+			testhelper.DiffString(t, tc.IDStr(), "text",
+				string(text), tc.expText)
+		}
+
+		js, err := json.Marshal(tc.sv)
+		if err != nil {
+			t.Log(tc.IDStr())
+			t.Errorf("\t: unexpected error from json.Marshal: %s", err)
+		} else {
+			testhelper.DiffString(t, tc.IDStr(), "JSON",
+				string(js), tc.expJSON)
+		}
+	}
+}
+
+func TestUnmarshalText(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		text           string
+		prefixOptional bool
+		expSVStr       string
+		expSet         bool
+	}{
+		{
+			ID:       testhelper.MkID("empty"),
+			expSVStr: "",
+		},
+		{
+			ID:       testhelper.MkID("good"),
+			text:     "v1.2.3-rc.1+build.7",
+			expSVStr: "v1.2.3-rc.1+build.7",
+			expSet:   true,
+		},
+		{
+			ID:   testhelper.MkID("bad - no prefix"),
+			text: "1.2.3",
+			ExpErr: testhelper.MkExpErr(
+				"bad " + semver.Name + " - it does not start with a 'v'"),
+		},
+		{
+			ID:             testhelper.MkID("prefix optional - empty"),
+			prefixOptional: true,
+			expSVStr:       "",
+		},
+		{
+			ID:             testhelper.MkID("prefix optional - no prefix"),
+			text:           "1.2.3",
+			prefixOptional: true,
+			expSVStr:       "v1.2.3",
+			expSet:         true,
+		},
+		{
+			ID:             testhelper.MkID("prefix optional - with prefix"),
+			text:           "v1.2.3",
+			prefixOptional: true,
+			expSVStr:       "v1.2.3",
+			expSet:         true,
+		},
+		{
+			ID:             testhelper.MkID("bad - prefix optional"),
+			text:           "1.2",
+			prefixOptional: true,
+			ExpErr: testhelper.MkExpErr(
+				"bad " + semver.Name +
+					" - it cannot be split into major/minor/patch parts"),
+		},
+	}
+
+	for _, tc := range testCases {
+		var (
+			sv  *semver.SV
+			err error
+		)
+
+		if tc.prefixOptional {
+			posv := &semver.PrefixOptionalSV{}
+			err = posv.UnmarshalText([]byte(tc.text))
+			sv = &posv.SV
+		} else {
+			sv = semver.NewSVOrPanic(9, 9, 9, nil, nil)
+			err = sv.UnmarshalText([]byte(tc.text))
+		}
+
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			testhelper.DiffString(t, tc.IDStr(), "SV",
+				sv.String(), tc.expSVStr)
+			testhelper.DiffBool(t, tc.IDStr(), "has been set",
+				sv.HasBeenSet(), tc.expSet)
+		}
+	}
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	type cfg struct {
+		Vsn    semver.SV
+		VsnPtr *semver.SV
+		VsnOpt semver.PrefixOptionalSV
+	}
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		js           string
+		expVsn       string
+		expVsnSet    bool
+		expVsnPtr    string
+		expVsnOpt    string
+		expRoundTrip string
+	}{
+		{
+			ID:           testhelper.MkID("all null"),
+			js:           `{"Vsn":null,"VsnPtr":null,"VsnOpt":null}`,
+			expVsnPtr:    "nil",
+			expRoundTrip: `{"Vsn":null,"VsnPtr":null,"VsnOpt":null}`,
+		},
+		{
+			ID:           testhelper.MkID("empty strings"),
+			js:           `{"Vsn":"","VsnPtr":"","VsnOpt":""}`,
+			expVsnPtr:    "",
+			expRoundTrip: `{"Vsn":null,"VsnPtr":null,"VsnOpt":null}`,
+		},
+		{
+			ID: testhelper.MkID("all set"),
+			js: `{"Vsn":"v1.2.3","VsnPtr":"v2.0.0-rc.1",` +
+				`"VsnOpt":"3.4.5"}`,
+			expVsn:    "v1.2.3",
+			expVsnSet: true,
+			expVsnPtr: "v2.0.0-rc.1",
+			expVsnOpt: "v3.4.5",
+			expRoundTrip: `{"Vsn":"v1.2.3","VsnPtr":"v2.0.0-rc.1",` +
+				`"VsnOpt":"v3.4.5"}`,
+		},
+		{
+			ID: testhelper.MkID("bad - no prefix"),
+			js: `{"Vsn":"1.2.3"}`,
+			ExpErr: testhelper.MkExpErr(
+				"bad " + semver.Name + " - it does not start with a 'v'"),
+		},
+		{
+			ID: testhelper.MkID("bad - not a string"),
+			js: `{"Vsn":123}`,
+			ExpErr: testhelper.MkExpErr(
+				"bad " + semver.Name + " - the JSON value must be a string"),
+		},
+	}
+
+	for _, tc := range testCases {
+		var c cfg
+
+		err := json.Unmarshal([]byte(tc.js), &c)
+		if !testhelper.CheckExpErr(t, err, tc) || err != nil {
+			continue
+		}
+
+		testhelper.DiffString(t, tc.IDStr(), "Vsn", c.Vsn.String(), tc.expVsn)
+		testhelper.DiffBool(t, tc.IDStr(), "Vsn has been set",
+			c.Vsn.HasBeenSet(), tc.expVsnSet)
+		testhelper.DiffString(t, tc.IDStr(), "VsnPtr",
+			svStr(c.VsnPtr), tc.expVsnPtr)
+		testhelper.DiffString(t, tc.IDStr(), "VsnOpt",
+			c.VsnOpt.String(), tc.expVsnOpt)
+
+		js, err := json.Marshal(c)
+		if err != nil {
+			t.Log(tc.IDStr())
+			t.Errorf("\t: unexpected error from json.Marshal: %s", err)
+
+			continue
+		}
+
+		testhelper.DiffString(t, tc.IDStr(), "round trip",
+			string(js), tc.expRoundTrip)
+	}
+}
@@ -0,0 +1,360 @@
+package semver
+
+import (
+	"bufio"
+	"errors"
+	"io"
+)
+
+// Match records a semantic version ID found in some text
+type Match struct {
+	// SV is the semantic version ID that was found
+	SV *SV
+	// Start is the byte offset of the start of the version (including any
+	// leading 'v')
+	Start int
+	// End is the byte offset just after the end of the version
+	End int
+}
+
+// byteSeq is the set of types that can be searched for versions
+type byteSeq interface {
+	~string | ~[]byte
+}
+
+// findState is the state of the machine recognising a version
+type findState int
+
+const (
+	fsMajor findState = iota
+	fsMinor
+	fsPatch
+	fsPreRel
+	fsBuild
+	fsDone
+)
+
+// span records the start and end offsets of part of a version
+type span struct {
+	start, end int
+}
+
+// isAlnum returns true if the byte is an ASCII letter or digit
+func isAlnum(b byte) bool {
+	return isDigit(b) || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z'
+}
+
+// isIDChar returns true if the byte can appear in a pre-release or build ID
+func isIDChar(b byte) bool {
+	return isAlnum(b) || b == '-'
+}
+
+// blocksStart returns true if a version cannot start straight after the
+// byte. This stops versions being found in the middle of words, numbers or
+// longer dotted sequences.
+func blocksStart(b byte) bool {
+	return isAlnum(b) || b == '.' || b == '_'
+}
+
+// blocksEnd returns true if a version cannot end at offset i of the text.
+// This stops versions being found at the start of words, numbers or longer
+// dotted sequences such as IP addresses and stops a version being found if
+// it runs straight into more version characters, as in "1.2.3+a+b".
+func blocksEnd[T byteSeq](text T, i int) bool {
+	if i >= len(text) {
+		return false
+	}
+
+	b := text[i]
+
+	return isAlnum(b) || b == '_' ||
+		b == semverBuildIDsSeparator[0] ||
+		b == semverPartSeparator[0] && i+1 < len(text) && isDigit(text[i+1])
+}
+
+// continuesVersion returns true if the byte at offset i of the text, just
+// after a '.', shows that the '.' is part of the version rather than
+// punctuation after it. This is so if it is a character that can start an
+// ID or if it is another separator, in which case the version is bad.
+func continuesVersion[T byteSeq](text T, i int) bool {
+	if i >= len(text) {
+		return false
+	}
+
+	b := text[i]
+
+	return isIDChar(b) ||
+		b == semverPartSeparator[0] || b == semverBuildIDsSeparator[0]
+}
+
+// isStart returns true if a version could start at offset i of the text
+func isStart[T byteSeq](text T, i int) bool {
+	if i > 0 && blocksStart(text[i-1]) {
+		return false
+	}
+
+	if text[i] == semverPrefix[0] {
+		return i+1 < len(text) && isDigit(text[i+1])
+	}
+
+	return isDigit(text[i])
+}
+
+// scanWhile returns the offset of the first byte at or after offset i for
+// which the func returns false
+func scanWhile[T byteSeq](text T, i int, f func(byte) bool) int {
+	for i < len(text) && f(text[i]) {
+		i++
+	}
+
+	return i
+}
+
+// hasLeadingZero returns true if the span holds a number with a leading
+// zero
+func hasLeadingZero[T byteSeq](text T, s span) bool {
+	return s.end-s.start > 1 && text[s.start] == '0'
+}
+
+// isNumeric returns true if the span holds only digits
+func isNumeric[T byteSeq](text T, s span) bool {
+	return scanWhile(text, s.start, isDigit) >= s.end
+}
+
+// looksLikeDate returns true if the major, minor and patch versions could
+// be a year, month and day
+func looksLikeDate(sv *SV) bool {
+	const (
+		minYear = 1900
+		maxYear = 2099
+		maxMon  = 12
+		maxDay  = 31
+	)
+
+	return sv.major.cmpInt(minYear) >= 0 && sv.major.cmpInt(maxYear) <= 0 &&
+		sv.minor.cmpInt(1) >= 0 && sv.minor.cmpInt(maxMon) <= 0 &&
+		sv.patch.cmpInt(1) >= 0 && sv.patch.cmpInt(maxDay) <= 0
+}
+
+// matchAt returns the version starting at offset start of the text. The
+// boolean return value is false if there is no valid version there.
+//
+//nolint:cyclop
+func matchAt[T byteSeq](text T, start int) (Match, bool) {
+	var (
+		vNums     [semverVsnPartCount]span
+		preRelIDs []span
+		buildIDs  []span
+	)
+
+	i := start
+	hasPrefix := text[i] == semverPrefix[0]
+
+	if hasPrefix {
+		i++
+	}
+
+	end := i
+	state := fsMajor
+
+	for state != fsDone {
+		switch state {
+		case fsMajor, fsMinor, fsPatch:
+			s := span{start: i, end: scanWhile(text, i, isDigit)}
+			if s.start == s.end || hasLeadingZero(text, s) {
+				return Match{}, false
+			}
+
+			vNums[state-fsMajor] = s
+			i = s.end
+
+			if state != fsPatch {
+				if i >= len(text) || text[i] != semverPartSeparator[0] {
+					return Match{}, false
+				}
+
+				i++
+				state++
+
+				continue
+			}
+
+			end = i
+			state = fsDone
+
+			if i < len(text) {
+				switch text[i] {
+				case semverPreRelIDsSeparator[0]:
+					state = fsPreRel
+					i++
+				case semverBuildIDsSeparator[0]:
+					state = fsBuild
+					i++
+				}
+			}
+		case fsPreRel, fsBuild:
+			s := span{start: i, end: scanWhile(text, i, isIDChar)}
+			if s.start == s.end {
+				// a separator must be followed by an ID
+				return Match{}, false
+			}
+
+			if state == fsPreRel {
+				if isNumeric(text, s) && hasLeadingZero(text, s) {
+					return Match{}, false
+				}
+
+				preRelIDs = append(preRelIDs, s)
+			} else {
+				buildIDs = append(buildIDs, s)
+			}
+
+			i = s.end
+			end = i
+
+			switch {
+			case i >= len(text):
+				state = fsDone
+			case text[i] == semverPartSeparator[0]:
+				if !continuesVersion(text, i+1) {
+					// the '.' is punctuation after the version
+					state = fsDone
+
+					continue
+				}
+
+				i++
+			case state == fsPreRel && text[i] == semverBuildIDsSeparator[0]:
+				state = fsBuild
+				i++
+			default:
+				state = fsDone
+			}
+		}
+	}
+
+	if blocksEnd(text, end) {
+		return Match{}, false
+	}
+
+	sv := &SV{hasBeenSet: true}
+
+	for i, p := range []*vNum{&sv.major, &sv.minor, &sv.patch} {
+		*p = vNum(text[vNums[i].start:vNums[i].end])
+	}
+
+	if !hasPrefix && looksLikeDate(sv) {
+		return Match{}, false
+	}
+
+	for _, s := range preRelIDs {
+		sv.preRelIDs = append(sv.preRelIDs, string(text[s.start:s.end]))
+	}
+
+	for _, s := range buildIDs {
+		sv.buildIDs = append(sv.buildIDs, string(text[s.start:s.end]))
+	}
+
+	return Match{SV: sv, Start: start, End: end}, true
+}
+
+// findAll returns all the versions in the text. The offset is added to the
+// Start and End of each Match.
+func findAll[T byteSeq](text T, offset int) []Match {
+	matches := []Match{}
+
+	for i := 0; i < len(text); {
+		if isStart(text, i) {
+			if m, ok := matchAt(text, i); ok {
+				m.Start += offset
+				m.End += offset
+				matches = append(matches, m)
+				i = m.End - offset
+
+				continue
+			}
+		}
+
+		i++
+	}
+
+	return matches
+}
+
+// FindAll returns every semantic version ID in the string, with or without
+// the leading 'v', together with its position. Only versions which follow
+// the rules of the Semantic Versioning Specification are found.
+//
+// To avoid false positives a version is only found if it is not part of a
+// longer word or number. So, for instance, no version is found in an IP
+// address such as "192.168.1.1" or in "1.2.3.4" or "abc1.2.3". This
+// deliberately excludes Go toolchain names such as "go1.22.3"; Coerce,
+// allowing CoerceSurroundingText, will extract the version from those. A
+// candidate which is not a complete, valid version is skipped rather than
+// shortened, so every '-', '+' or '.' in the pre-release and build IDs
+// must be followed by a valid ID and no version is found in "1.2.3-" or
+// "v1.2.3-rc..1". A '.' which is not followed by a version character is
+// taken to be punctuation, so the version ends before it; this means that
+// a version at the end of a sentence, such as "v1.2.3-rc.1.", is found. Also,
+// a version without the leading 'v' whose parts could be a year, a month
+// and a day (such as "2024.6.30") is taken to be a date and is not found.
+func FindAll(s string) []Match {
+	return findAll(s, 0)
+}
+
+// FindAllBytes returns every semantic version ID in the slice of bytes
+// together with its position. See FindAll for details.
+func FindAllBytes(b []byte) []Match {
+	return findAll(b, 0)
+}
+
+// Scanner finds the semantic version IDs in the text read from an
+// io.Reader. The text is read a line at a time and the versions are found
+// as for FindAll. The Start and End of each Match are offsets from the
+// start of the text.
+type Scanner struct {
+	r       *bufio.Reader
+	offset  int
+	pending []Match
+	match   Match
+	done    bool
+	err     error
+}
+
+// NewScanner returns a Scanner reading from the io.Reader
+func NewScanner(r io.Reader) *Scanner {
+	return &Scanner{r: bufio.NewReader(r)}
+}
+
+// Scan advances the Scanner to the next version, which is then available
+// through the Match method. It returns false when there are no more
+// versions or if an error occurred while reading; the Err method will
+// report any error.
+func (s *Scanner) Scan() bool {
+	for len(s.pending) == 0 {
+		if s.done {
+			return false
+		}
+
+		line, err := s.r.ReadBytes('\n')
+		s.pending = findAll(line, s.offset)
+		s.offset += len(line)
+
+		if err != nil {
+			s.done = true
+			if !errors.Is(err, io.EOF) {
+				s.err = err
+			}
+		}
+	}
+
+	s.match, s.pending = s.pending[0], s.pending[1:]
+
+	return true
+}
+
+// Match returns the most recent version found by Scan
+func (s *Scanner) Match() Match { return s.match }
+
+// Err returns the first error, other than io.EOF, met while reading
+func (s *Scanner) Err() error { return s.err }
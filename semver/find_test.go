@@ -0,0 +1,292 @@
+package semver_test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/nickwells/semver.mod/v3/semver"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+// matchStrs returns a string for each Match giving the SV and its span
+func matchStrs(t *testing.T, id, text string, matches []semver.Match,
+) []string {
+	t.Helper()
+
+	strs := []string{}
+
+	for _, m := range matches {
+		if m.Start < 0 || m.End > len(text) || m.Start >= m.End {
+			t.Log(id)
+			t.Errorf("\t: bad match span: [%d:%d]", m.Start, m.End)
+
+			continue
+		}
+
+		strs = append(strs, svStr(m.SV)+" "+text[m.Start:m.End])
+	}
+
+	return strs
+}
+
+func TestFindAll(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		text       string
+		expMatches []string
+	}{
+		{
+			ID:         testhelper.MkID("empty"),
+			expMatches: []string{},
+		},
+		{
+			ID:         testhelper.MkID("just a version"),
+			text:       "v1.2.3",
+			expMatches: []string{"v1.2.3 v1.2.3"},
+		},
+		{
+			ID:         testhelper.MkID("no prefix"),
+			text:       "version 1.2.3 is out",
+			expMatches: []string{"v1.2.3 1.2.3"},
+		},
+		{
+			ID:   testhelper.MkID("pre-release and build IDs"),
+			text: "FROM golang:1.22.3-alpine+x.y AS build",
+			expMatches: []string{
+				"v1.22.3-alpine+x.y 1.22.3-alpine+x.y",
+			},
+		},
+		{
+			ID:   testhelper.MkID("several versions"),
+			text: "## [v2.0.0] - upgrade from 1.9.4, (was 1.9.3-rc.1).",
+			expMatches: []string{
+				"v2.0.0 v2.0.0",
+				"v1.9.4 1.9.4",
+				"v1.9.3-rc.1 1.9.3-rc.1",
+			},
+		},
+		{
+			ID:         testhelper.MkID("hyphenated name"),
+			text:       "foo-1.2.3.tar.gz",
+			expMatches: []string{"v1.2.3 1.2.3"},
+		},
+		{
+			ID:         testhelper.MkID("trailing separators"),
+			text:       "1.2.3- 4.5.6+ v1.2.3-rc+ v1.2.3-rc.+b",
+			expMatches: []string{},
+		},
+		{
+			ID:   testhelper.MkID("trailing full stop after IDs"),
+			text: "7.8.9-rc. 1.0.0-a+b. (v2.0.0-rc.1.)",
+			expMatches: []string{
+				"v7.8.9-rc 7.8.9-rc",
+				"v1.0.0-a+b 1.0.0-a+b",
+				"v2.0.0-rc.1 v2.0.0-rc.1",
+			},
+		},
+		{
+			ID:         testhelper.MkID("trailing hyphen at the end"),
+			text:       "1.2.3-",
+			expMatches: []string{},
+		},
+		{
+			ID:         testhelper.MkID("trailing plus at the end"),
+			text:       "v1.2.3+",
+			expMatches: []string{},
+		},
+		{
+			ID:         testhelper.MkID("trailing full stop"),
+			text:       "Upgrade to v1.2.3.",
+			expMatches: []string{"v1.2.3 v1.2.3"},
+		},
+		{
+			ID:         testhelper.MkID("empty IDs"),
+			text:       "v1.2.3-rc..1 1.2.3-.rc 1.2.3+a..b 1.2.3-rc+.b",
+			expMatches: []string{},
+		},
+		{
+			ID:         testhelper.MkID("empty ID, alone"),
+			text:       "v1.2.3-rc..1",
+			expMatches: []string{},
+		},
+		{
+			ID:   testhelper.MkID("runs into more version characters"),
+			text: "1.2.3+a+b v1.2.3-rc+b+c 1.2.3-rc.1.2.3.4+x.5.6",
+			expMatches: []string{
+				"v1.2.3-rc.1.2.3.4+x.5.6 1.2.3-rc.1.2.3.4+x.5.6",
+			},
+		},
+		{
+			ID:         testhelper.MkID("bad pre-release ID"),
+			text:       "1.2.3-rc.01 v1.2.3-rc_1",
+			expMatches: []string{},
+		},
+		{
+			ID:         testhelper.MkID("Go toolchain names"),
+			text:       "go1.22.3 go1.22rc1",
+			expMatches: []string{},
+		},
+		{
+			ID:         testhelper.MkID("IP addresses"),
+			text:       "connect to 192.168.1.1 or 10.0.0.255:80",
+			expMatches: []string{},
+		},
+		{
+			ID:         testhelper.MkID("too many parts"),
+			text:       "1.2.3.4",
+			expMatches: []string{},
+		},
+		{
+			ID:         testhelper.MkID("part of a word or number"),
+			text:       "abc1.2.3 dev1.2.3 1.2.3abc 11.2.3x _1.2.3 1.2.3_",
+			expMatches: []string{},
+		},
+		{
+			ID:         testhelper.MkID("dates"),
+			text:       "2024.01.15 2024.6.30 on 1999.12.31",
+			expMatches: []string{},
+		},
+		{
+			ID:         testhelper.MkID("date-like with a prefix"),
+			text:       "v2024.6.30",
+			expMatches: []string{"v2024.6.30 v2024.6.30"},
+		},
+		{
+			ID:         testhelper.MkID("leading zeros"),
+			text:       "01.2.3 1.02.3 1.2.03 1.2.3-rc.01",
+			expMatches: []string{},
+		},
+		{
+			ID:   testhelper.MkID("big numbers"),
+			text: "99999999999999999999.0.0",
+			expMatches: []string{
+				"v99999999999999999999.0.0 99999999999999999999.0.0",
+			},
+		},
+		{
+			ID:         testhelper.MkID("missing parts"),
+			text:       "v1.2 1. v",
+			expMatches: []string{},
+		},
+		{
+			ID:         testhelper.MkID("hyphens in pre-release IDs"),
+			text:       "(1.2.3--x-y.-)",
+			expMatches: []string{"v1.2.3--x-y.- 1.2.3--x-y.-"},
+		},
+	}
+
+	for _, tc := range testCases {
+		testhelper.DiffStringSlice(t, tc.IDStr(), "FindAll",
+			matchStrs(t, tc.IDStr(), tc.text, semver.FindAll(tc.text)),
+			tc.expMatches)
+		testhelper.DiffStringSlice(t, tc.IDStr(), "FindAllBytes",
+			matchStrs(t, tc.IDStr(), tc.text,
+				semver.FindAllBytes([]byte(tc.text))),
+			tc.expMatches)
+	}
+}
+
+func TestFindAllSpans(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		text     string
+		expSV    string
+		expStart int
+		expEnd   int
+	}{
+		{
+			ID:       testhelper.MkID("sentence-final pre-release"),
+			text:     "Released v1.2.3-rc.1.",
+			expSV:    "v1.2.3-rc.1",
+			expStart: 9,
+			expEnd:   20,
+		},
+		{
+			ID:       testhelper.MkID("sentence-final build"),
+			text:     "v1.2.3+b.",
+			expSV:    "v1.2.3+b",
+			expStart: 0,
+			expEnd:   8,
+		},
+		{
+			ID:       testhelper.MkID("sentence-final pre-release and build"),
+			text:     "See 1.0.0-beta.2+exp.sha.5114f85. It works.",
+			expSV:    "v1.0.0-beta.2+exp.sha.5114f85",
+			expStart: 4,
+			expEnd:   32,
+		},
+		{
+			ID:       testhelper.MkID("sentence-final release"),
+			text:     "see v1.2.3.",
+			expSV:    "v1.2.3",
+			expStart: 4,
+			expEnd:   10,
+		},
+	}
+
+	for _, tc := range testCases {
+		matches := semver.FindAll(tc.text)
+		if len(matches) != 1 {
+			t.Log(tc.IDStr())
+			t.Errorf("\t: expected 1 match, got %d", len(matches))
+
+			continue
+		}
+
+		m := matches[0]
+		testhelper.DiffString(t, tc.IDStr(), "SV", m.SV.String(), tc.expSV)
+		testhelper.DiffInt(t, tc.IDStr(), "Start", m.Start, tc.expStart)
+		testhelper.DiffInt(t, tc.IDStr(), "End", m.End, tc.expEnd)
+	}
+}
+
+func TestScanner(t *testing.T) {
+	const text = "v1.0.0\n" +
+		"no versions here: 10.0.0.1\n" +
+		"\n" +
+		"1.2.3 and v2.0.0-rc.1+b\n" +
+		"last line 3.4.5"
+
+	expMatches := []string{
+		"v1.0.0 v1.0.0",
+		"v1.2.3 1.2.3",
+		"v2.0.0-rc.1+b v2.0.0-rc.1+b",
+		"v3.4.5 3.4.5",
+	}
+
+	s := semver.NewScanner(iotest.OneByteReader(strings.NewReader(text)))
+
+	var matches []semver.Match
+	for s.Scan() {
+		matches = append(matches, s.Match())
+	}
+
+	if err := s.Err(); err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+
+	testhelper.DiffStringSlice(t, "Scanner", "matches",
+		matchStrs(t, "Scanner", text, matches), expMatches)
+}
+
+func TestScannerErr(t *testing.T) {
+	readErr := errors.New("read failed")
+	r := io.MultiReader(
+		strings.NewReader("v1.0.0\nv2.0.0"), iotest.ErrReader(readErr))
+
+	s := semver.NewScanner(r)
+
+	count := 0
+	for s.Scan() {
+		count++
+	}
+
+	testhelper.DiffInt(t, "Scanner", "match count", count, 2)
+
+	if !errors.Is(s.Err(), readErr) {
+		t.Errorf("unexpected error: %v", s.Err())
+	}
+}
@@ -0,0 +1,194 @@
+package semver
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nickwells/check.mod/v2/check"
+)
+
+const svListFlagSeparator = ","
+
+// PrefixRule controls whether a version given as a flag value must have the
+// leading 'v'
+type PrefixRule int
+
+const (
+	// PrefixRequired means that the version must start with a 'v' (see
+	// ParseSV)
+	PrefixRequired PrefixRule = iota
+	// PrefixForbidden means that the version must not start with a 'v' (see
+	// ParseStrictSV)
+	PrefixForbidden
+	// PrefixOptional means that the version may or may not start with a 'v'
+	PrefixOptional
+)
+
+// FlagOpts holds the options controlling how the flag types parse a
+// version
+type FlagOpts struct {
+	// PrefixRule controls whether the version must have the leading 'v'
+	PrefixRule PrefixRule
+	// PreRelIDRules and BuildIDRules are additional checks on the
+	// pre-release and build IDs (see NewSVWithIDRules)
+	PreRelIDRules []check.ValCk[[]string]
+	BuildIDRules  []check.ValCk[[]string]
+}
+
+// parse parses the string into an SV according to the options
+func (fo FlagOpts) parse(s string) (*SV, error) {
+	var (
+		sv  *SV
+		err error
+	)
+
+	switch fo.PrefixRule {
+	case PrefixRequired:
+		sv, err = ParseSV(s)
+	case PrefixForbidden:
+		sv, err = ParseStrictSV(s)
+	case PrefixOptional:
+		sv, err = parseOptPfx(s)
+	default:
+		return nil, fmt.Errorf("bad PrefixRule: %d", fo.PrefixRule)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(fo.PreRelIDRules) == 0 && len(fo.BuildIDRules) == 0 {
+		return sv, nil
+	}
+
+	if err := CheckRules(sv.preRelIDs, fo.PreRelIDRules); err != nil {
+		return nil, err
+	}
+
+	if err := CheckRules(sv.buildIDs, fo.BuildIDRules); err != nil {
+		return nil, err
+	}
+
+	return sv, nil
+}
+
+// SVFlag can be used as a flag.Value to set an SV from a command-line
+// parameter. For instance:
+//
+//	var minVsn semver.SV
+//	flag.Var(&semver.SVFlag{Value: &minVsn}, "version-min", "...")
+type SVFlag struct {
+	Value *SV
+	FlagOpts
+}
+
+// Set parses the value and sets the SV from it. This satisfies the
+// flag.Value interface.
+func (f *SVFlag) Set(value string) error {
+	sv, err := f.parse(value)
+	if err != nil {
+		return err
+	}
+
+	if f.Value == nil {
+		f.Value = sv
+	} else {
+		*f.Value = *sv
+	}
+
+	return nil
+}
+
+// String returns the string form of the SV. This satisfies the flag.Value
+// interface.
+func (f *SVFlag) String() string {
+	if f == nil || f.Value == nil {
+		return ""
+	}
+
+	return f.Value.String()
+}
+
+// ConstraintFlag can be used as a flag.Value to set a Constraint from a
+// command-line parameter. The Constraint is parsed according to the rules
+// of the Dialect (see ParseConstraintDialect).
+type ConstraintFlag struct {
+	Value   *Constraint
+	Dialect Dialect
+}
+
+// Set parses the value and sets the Constraint from it. This satisfies the
+// flag.Value interface.
+func (f *ConstraintFlag) Set(value string) error {
+	c, err := ParseConstraintDialect(value, f.Dialect)
+	if err != nil {
+		return err
+	}
+
+	if f.Value == nil {
+		f.Value = c
+	} else {
+		*f.Value = *c
+	}
+
+	return nil
+}
+
+// String returns the string form of the Constraint. This satisfies the
+// flag.Value interface.
+func (f *ConstraintFlag) String() string {
+	if f == nil || f.Value == nil {
+		return ""
+	}
+
+	return f.Value.String()
+}
+
+// SVListFlag can be used as a flag.Value to accumulate SVs from
+// command-line parameters. Each time the flag is given the versions are
+// added to the end of the SVList. A single value may hold several versions
+// separated by commas.
+type SVListFlag struct {
+	Value *SVList
+	FlagOpts
+}
+
+// Set parses the value and adds the SVs to the end of the SVList. If any of
+// the versions cannot be parsed none are added. This satisfies the
+// flag.Value interface.
+func (f *SVListFlag) Set(value string) error {
+	parts := strings.Split(value, svListFlagSeparator)
+	svl := make(SVList, 0, len(parts))
+
+	for _, part := range parts {
+		sv, err := f.parse(strings.TrimSpace(part))
+		if err != nil {
+			return err
+		}
+
+		svl = append(svl, sv)
+	}
+
+	if f.Value == nil {
+		f.Value = &SVList{}
+	}
+
+	*f.Value = append(*f.Value, svl...)
+
+	return nil
+}
+
+// String returns the SVs in the SVList separated by commas. This satisfies
+// the flag.Value interface.
+func (f *SVListFlag) String() string {
+	if f == nil || f.Value == nil {
+		return ""
+	}
+
+	strs := make([]string, 0, len(*f.Value))
+	for _, sv := range *f.Value {
+		strs = append(strs, sv.String())
+	}
+
+	return strings.Join(strs, svListFlagSeparator)
+}
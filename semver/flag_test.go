@@ -0,0 +1,230 @@
+package semver_test
+
+import (
+	"flag"
+	"io"
+	"testing"
+
+	"github.com/nickwells/check.mod/v2/check"
+	"github.com/nickwells/semver.mod/v3/semver"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+// mkFlagSet returns a FlagSet which reports errors rather than exiting
+func mkFlagSet() *flag.FlagSet {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	return fs
+}
+
+func TestSVFlag(t *testing.T) {
+	noBuildIDs := []check.ValCk[[]string]{
+		check.SliceLength[[]string](check.ValEQ(0)),
+	}
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		opts     semver.FlagOpts
+		args     []string
+		expSVStr string
+	}{
+		{
+			ID:       testhelper.MkID("not given"),
+			expSVStr: "v9.9.9",
+		},
+		{
+			ID:       testhelper.MkID("prefix required"),
+			args:     []string{"-vsn", "v1.2.3-rc.1+b"},
+			expSVStr: "v1.2.3-rc.1+b",
+		},
+		{
+			ID:   testhelper.MkID("bad - prefix required"),
+			args: []string{"-vsn", "1.2.3"},
+			ExpErr: testhelper.MkExpErr(
+				`invalid value "1.2.3" for flag -vsn`,
+				"it does not start with a 'v'"),
+		},
+		{
+			ID:       testhelper.MkID("prefix forbidden"),
+			opts:     semver.FlagOpts{PrefixRule: semver.PrefixForbidden},
+			args:     []string{"-vsn", "1.2.3"},
+			expSVStr: "v1.2.3",
+		},
+		{
+			ID:   testhelper.MkID("bad - prefix forbidden"),
+			opts: semver.FlagOpts{PrefixRule: semver.PrefixForbidden},
+			args: []string{"-vsn", "v1.2.3"},
+			ExpErr: testhelper.MkExpErr(
+				`invalid value "v1.2.3" for flag -vsn`,
+				`the major version: "v1" is not an integer`),
+		},
+		{
+			ID:       testhelper.MkID("prefix optional - no prefix"),
+			opts:     semver.FlagOpts{PrefixRule: semver.PrefixOptional},
+			args:     []string{"-vsn", "1.2.3"},
+			expSVStr: "v1.2.3",
+		},
+		{
+			ID:       testhelper.MkID("prefix optional - with prefix"),
+			opts:     semver.FlagOpts{PrefixRule: semver.PrefixOptional},
+			args:     []string{"-vsn", "v1.2.3"},
+			expSVStr: "v1.2.3",
+		},
+		{
+			ID:       testhelper.MkID("ID rules"),
+			opts:     semver.FlagOpts{BuildIDRules: noBuildIDs},
+			args:     []string{"-vsn", "v1.2.3-rc.1"},
+			expSVStr: "v1.2.3-rc.1",
+		},
+		{
+			ID:   testhelper.MkID("bad - ID rules"),
+			opts: semver.FlagOpts{BuildIDRules: noBuildIDs},
+			args: []string{"-vsn", "v1.2.3+b"},
+			ExpErr: testhelper.MkExpErr(
+				`invalid value "v1.2.3+b" for flag -vsn`,
+				"the length of the list (1) is incorrect"),
+		},
+	}
+
+	for _, tc := range testCases {
+		sv := semver.NewSVOrPanic(9, 9, 9, nil, nil)
+		fs := mkFlagSet()
+		fs.Var(&semver.SVFlag{Value: sv, FlagOpts: tc.opts}, "vsn", "version")
+
+		err := fs.Parse(tc.args)
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			testhelper.DiffString(t, tc.IDStr(), "SV", sv.String(), tc.expSVStr)
+		}
+	}
+}
+
+func TestSVFlagNilValue(t *testing.T) {
+	f := &semver.SVFlag{}
+	testhelper.DiffString(t, "nil value", "String()", f.String(), "")
+
+	if err := f.Set("v1.2.3"); err != nil {
+		t.Fatal("unexpected error from Set: ", err)
+	}
+
+	testhelper.DiffString(t, "nil value", "Value", svStr(f.Value), "v1.2.3")
+	testhelper.DiffString(t, "nil value", "String()", f.String(), "v1.2.3")
+}
+
+func TestConstraintFlag(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		dialect  semver.Dialect
+		args     []string
+		expCStr  string
+		svStr    string
+		expCheck bool
+	}{
+		{
+			ID:       testhelper.MkID("npm"),
+			args:     []string{"-c", "^1.2.3"},
+			expCStr:  ">=1.2.3 <2.0.0",
+			svStr:    "v1.9.0",
+			expCheck: true,
+		},
+		{
+			ID:       testhelper.MkID("cargo"),
+			dialect:  semver.DialectCargo,
+			args:     []string{"-c", "1.2.3, <1.5"},
+			expCStr:  ">=1.2.3, <2.0.0, <1.5.0",
+			svStr:    "v1.9.0",
+			expCheck: false,
+		},
+		{
+			ID:   testhelper.MkID("bad - npm with a comma"),
+			args: []string{"-c", ">=1.2.3,<2"},
+			ExpErr: testhelper.MkExpErr(
+				`invalid value ">=1.2.3,<2" for flag -c`,
+				"bad version constraint"),
+		},
+	}
+
+	for _, tc := range testCases {
+		var c semver.Constraint
+
+		fs := mkFlagSet()
+		fs.Var(&semver.ConstraintFlag{Value: &c, Dialect: tc.dialect},
+			"c", "constraint")
+
+		err := fs.Parse(tc.args)
+		if !testhelper.CheckExpErr(t, err, tc) || err != nil {
+			continue
+		}
+
+		testhelper.DiffString(t, tc.IDStr(), "constraint",
+			c.String(), tc.expCStr)
+
+		sv, err := semver.ParseSV(tc.svStr)
+		if err != nil {
+			t.Fatal("Couldn't parse the semver: ", err)
+		}
+
+		testhelper.DiffBool(t, tc.IDStr(), "check", c.Check(sv), tc.expCheck)
+	}
+}
+
+func TestSVListFlag(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		opts      semver.FlagOpts
+		args      []string
+		expSVStrs []string
+		expStr    string
+	}{
+		{
+			ID:        testhelper.MkID("none"),
+			expSVStrs: []string{},
+		},
+		{
+			ID:        testhelper.MkID("repeated flags"),
+			args:      []string{"-v", "v1.2.3", "-v", "v0.1.0"},
+			expSVStrs: []string{"v1.2.3", "v0.1.0"},
+			expStr:    "v1.2.3,v0.1.0",
+		},
+		{
+			ID:   testhelper.MkID("comma separated"),
+			opts: semver.FlagOpts{PrefixRule: semver.PrefixOptional},
+			args: []string{"-v", "1.2.3, v2.0.0", "-v", "3.0.0-rc.1"},
+			expSVStrs: []string{
+				"v1.2.3", "v2.0.0", "v3.0.0-rc.1",
+			},
+			expStr: "v1.2.3,v2.0.0,v3.0.0-rc.1",
+		},
+		{
+			ID:   testhelper.MkID("bad - one bad version"),
+			args: []string{"-v", "v1.2.3,1.2.4"},
+			ExpErr: testhelper.MkExpErr(
+				`invalid value "v1.2.3,1.2.4" for flag -v`,
+				"it does not start with a 'v'"),
+		},
+	}
+
+	for _, tc := range testCases {
+		svl := semver.SVList{}
+		f := &semver.SVListFlag{Value: &svl, FlagOpts: tc.opts}
+		fs := mkFlagSet()
+		fs.Var(f, "v", "versions")
+
+		err := fs.Parse(tc.args)
+		if !testhelper.CheckExpErr(t, err, tc) || err != nil {
+			continue
+		}
+
+		got := make([]string, 0, len(svl))
+		for _, sv := range svl {
+			got = append(got, sv.String())
+		}
+
+		testhelper.DiffStringSlice(t, tc.IDStr(), "versions",
+			got, tc.expSVStrs)
+		testhelper.DiffString(t, tc.IDStr(), "String()", f.String(), tc.expStr)
+	}
+}
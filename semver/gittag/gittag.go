@@ -0,0 +1,241 @@
+/*
+Package gittag finds the semantic version tags in a local git repository
+so that the next version can be decided. It runs the git command to list
+the tags and to find which of them are on the ancestry of the current
+branch; git must be installed and on the PATH.
+
+A tag is a version tag if, once any prefix has been removed, it can be
+parsed by semver.ParseSV. Other tags are ignored. The prefix allows for
+repositories holding several modules, each tagged separately; for instance,
+the Go module in the "mymod" subdirectory is tagged "mymod/v1.2.3" and its
+tags are found using the prefix "mymod/".
+*/
+package gittag
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"github.com/nickwells/semver.mod/v3/semver"
+)
+
+// tagRefPrefix is the prefix of the full name of every tag ref
+const tagRefPrefix = "refs/tags/"
+
+// tagRefFormat is the format given to "git for-each-ref" to list the tags.
+// The peeled object name (the "*objectname") is only set for annotated
+// tags; it is the commit that the tag refers to.
+const tagRefFormat = "%(refname) %(objectname) %(*objectname)"
+
+// Tag records a version tag
+type Tag struct {
+	// Name is the full name of the tag, including any prefix
+	Name string
+	// SV is the semantic version given by the tag
+	SV *semver.SV
+	// Commit is the hash of the commit that the tag refers to
+	Commit string
+}
+
+// Latest records the latest version tags in a repository. Each of the
+// fields is nil if there is no such tag.
+type Latest struct {
+	// Release is the tag of the highest release version
+	Release *Tag
+	// PreRelease is the tag of the highest pre-release version
+	PreRelease *Tag
+	// OnBranch is the tag of the highest version, release or pre-release,
+	// which is on the ancestry of the current branch (of HEAD)
+	OnBranch *Tag
+}
+
+// Repo gives access to the version tags of a local git repository
+type Repo struct {
+	dir    string
+	prefix string
+}
+
+// NewRepo returns a Repo for the git repository containing the directory.
+// Only those tags starting with the prefix, which may be empty, are used.
+// It returns an error if the directory is not in a git repository.
+func NewRepo(dir, prefix string) (*Repo, error) {
+	r := &Repo{dir: dir, prefix: prefix}
+
+	if _, err := r.git("rev-parse", "--git-dir"); err != nil {
+		return nil, fmt.Errorf("%q is not in a git repository: %w", dir, err)
+	}
+
+	return r, nil
+}
+
+// Prefix returns the tag prefix
+func (r Repo) Prefix() string {
+	return r.prefix
+}
+
+// TagName returns the name of the tag for the version, with the prefix
+func (r Repo) TagName(sv *semver.SV) string {
+	return r.prefix + sv.String()
+}
+
+// git runs the git command in the repository directory with the arguments
+// and returns the standard output
+func (r Repo) git(args ...string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.Command("git", append([]string{"-C", r.dir}, args...)...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("git %s: %w: %s",
+			strings.Join(args, " "), err, bytes.TrimSpace(stderr.Bytes()))
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// hasHead returns true if HEAD refers to a commit. It is false in a new
+// repository before the first commit.
+func (r Repo) hasHead() bool {
+	_, err := r.git("rev-parse", "--verify", "--quiet", "HEAD^{commit}")
+
+	return err == nil
+}
+
+// listTags runs "git for-each-ref" with the extra arguments and returns the
+// version tags in ascending order
+func (r Repo) listTags(extraArgs ...string) ([]Tag, error) {
+	args := append([]string{"for-each-ref", "--format=" + tagRefFormat},
+		extraArgs...)
+
+	out, err := r.git(append(args, tagRefPrefix)...)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := []Tag{}
+
+	for line := range strings.Lines(string(out)) {
+		t, ok, err := r.parseTagRef(line)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			tags = append(tags, t)
+		}
+	}
+
+	slices.SortStableFunc(tags, func(a, b Tag) int {
+		return semver.CompareWithBuild(a.SV, b.SV)
+	})
+
+	return tags, nil
+}
+
+// parseTagRef parses a line of the output of "git for-each-ref". The
+// boolean return value is false if the ref is not a version tag with the
+// prefix.
+func (r Repo) parseTagRef(line string) (Tag, bool, error) {
+	const minFields = 2
+
+	fields := strings.Fields(line)
+	if len(fields) < minFields {
+		return Tag{}, false,
+			fmt.Errorf("unexpected output from git for-each-ref: %q", line)
+	}
+
+	name := strings.TrimPrefix(fields[0], tagRefPrefix)
+
+	vsn, ok := strings.CutPrefix(name, r.prefix)
+	if !ok {
+		return Tag{}, false, nil
+	}
+
+	sv, err := semver.ParseSV(vsn)
+	if err != nil {
+		return Tag{}, false, nil //nolint:nilerr
+	}
+
+	t := Tag{Name: name, SV: sv, Commit: fields[1]}
+	if len(fields) > minFields {
+		t.Commit = fields[minFields]
+	}
+
+	return t, true, nil
+}
+
+// Tags returns all the version tags with the prefix in ascending order of
+// version. Tags which differ only in their build IDs are ordered by the
+// build IDs (see semver.CompareWithBuild).
+func (r Repo) Tags() ([]Tag, error) {
+	return r.listTags()
+}
+
+// BranchTags returns the version tags with the prefix which are on the
+// ancestry of the current branch (of HEAD), in ascending order of version.
+func (r Repo) BranchTags() ([]Tag, error) {
+	if !r.hasHead() {
+		return []Tag{}, nil
+	}
+
+	return r.listTags("--merged", "HEAD")
+}
+
+// Latest returns the latest release, the latest pre-release and the latest
+// version on the current branch
+func (r Repo) Latest() (Latest, error) {
+	var l Latest
+
+	tags, err := r.Tags()
+	if err != nil {
+		return l, err
+	}
+
+	for i := len(tags) - 1; i >= 0; i-- {
+		t := &tags[i]
+
+		if t.SV.HasPreRelIDs() {
+			if l.PreRelease == nil {
+				l.PreRelease = t
+			}
+		} else if l.Release == nil {
+			l.Release = t
+		}
+	}
+
+	if len(tags) == 0 {
+		return l, nil
+	}
+
+	branchTags, err := r.BranchTags()
+	if err != nil {
+		return l, err
+	}
+
+	if len(branchTags) > 0 {
+		l.OnBranch = &branchTags[len(branchTags)-1]
+	}
+
+	return l, nil
+}
+
+// Next returns the version which follows the latest version on the
+// current branch. The func is called to move the version on; for instance,
+// (*semver.SV).IncrMinor. If there is no version on the branch the func is
+// applied to v0.0.0. The build IDs are removed.
+func (l Latest) Next(incr func(*semver.SV)) *semver.SV {
+	sv := semver.NewSVOrPanic(0, 0, 0, nil, nil)
+	if l.OnBranch != nil {
+		sv = l.OnBranch.SV.Clone()
+	}
+
+	incr(sv)
+	sv.ClearBuildIDs()
+
+	return sv
+}
@@ -0,0 +1,372 @@
+package gittag_test
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/nickwells/semver.mod/v3/semver"
+	"github.com/nickwells/semver.mod/v3/semver/gittag"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+// testRepo is a git repository created for testing
+type testRepo struct {
+	t       *testing.T
+	dir     string
+	commits int
+}
+
+// newTestRepo creates a new, empty, git repository in a temporary
+// directory. It skips the test if git is not available.
+func newTestRepo(t *testing.T) *testRepo {
+	t.Helper()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available: ", err)
+	}
+
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_AUTHOR_NAME", "test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	r := &testRepo{t: t, dir: t.TempDir()}
+	r.git("init", "--quiet", "--initial-branch=main")
+
+	return r
+}
+
+// git runs the git command in the repository and returns the output with
+// any trailing white space removed
+func (r *testRepo) git(args ...string) string {
+	r.t.Helper()
+
+	cmd := exec.Command("git", append([]string{"-C", r.dir}, args...)...)
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		r.t.Fatalf("git %s: %v: %s", strings.Join(args, " "), err, out)
+	}
+
+	return strings.TrimSpace(string(out))
+}
+
+// commit makes an empty commit, tags it with each of the tags and returns
+// its hash. Each commit has a different message so that commits made in
+// the same second on different branches have different hashes.
+func (r *testRepo) commit(tags ...string) string {
+	r.t.Helper()
+
+	r.commits++
+	r.git("commit", "--quiet", "--allow-empty", "--no-gpg-sign",
+		fmt.Sprintf("--message=commit %d", r.commits))
+
+	for _, tag := range tags {
+		r.git("tag", tag)
+	}
+
+	return r.git("rev-parse", "HEAD")
+}
+
+// tagNames returns the names of the tags
+func tagNames(tags []gittag.Tag) []string {
+	names := []string{}
+	for _, t := range tags {
+		names = append(names, t.Name)
+	}
+
+	return names
+}
+
+// latestName returns the name of the tag or "" if it is nil
+func latestName(t *gittag.Tag) string {
+	if t == nil {
+		return ""
+	}
+
+	return t.Name
+}
+
+// buildRepo creates a repository with these commits and tags:
+//
+//	main:  A (v1.0.0, mymod/v0.1.0, v1.1.0-rc.1 [annotated], junk)
+//	       B (v1.1.0, mymod/v0.2.0-alpha.1, v1.1.0+b.2, v1.1.0+b.1)
+//	maint: C from A (v1.0.1)
+//	next:  D from B (v2.0.0-beta.1)
+//
+// It returns the repository and the hash of commit A.
+func buildRepo(t *testing.T) (*testRepo, string) {
+	t.Helper()
+
+	r := newTestRepo(t)
+
+	commitA := r.commit("v1.0.0", "mymod/v0.1.0", "junk", "1.0.2")
+	r.git("tag", "--annotate", "--no-sign", "--message=rc",
+		"v1.1.0-rc.1")
+	r.commit("v1.1.0", "mymod/v0.2.0-alpha.1", "v1.1.0+b.2", "v1.1.0+b.1")
+
+	r.git("checkout", "--quiet", "-b", "maint", commitA)
+	r.commit("v1.0.1")
+
+	r.git("checkout", "--quiet", "-b", "next", "main")
+	r.commit("v2.0.0-beta.1")
+
+	r.git("checkout", "--quiet", "main")
+
+	return r, commitA
+}
+
+func TestTags(t *testing.T) {
+	r, commitA := buildRepo(t)
+
+	testCases := []struct {
+		testhelper.ID
+		prefix        string
+		branch        string
+		expTags       []string
+		expBranchTags []string
+		expRelease    string
+		expPreRelease string
+		expOnBranch   string
+	}{
+		{
+			ID:     testhelper.MkID("main"),
+			branch: "main",
+			expTags: []string{
+				"v1.0.0", "v1.0.1", "v1.1.0-rc.1",
+				"v1.1.0", "v1.1.0+b.1", "v1.1.0+b.2",
+				"v2.0.0-beta.1",
+			},
+			expBranchTags: []string{
+				"v1.0.0", "v1.1.0-rc.1",
+				"v1.1.0", "v1.1.0+b.1", "v1.1.0+b.2",
+			},
+			expRelease:    "v1.1.0+b.2",
+			expPreRelease: "v2.0.0-beta.1",
+			expOnBranch:   "v1.1.0+b.2",
+		},
+		{
+			ID:     testhelper.MkID("maint"),
+			branch: "maint",
+			expTags: []string{
+				"v1.0.0", "v1.0.1", "v1.1.0-rc.1",
+				"v1.1.0", "v1.1.0+b.1", "v1.1.0+b.2",
+				"v2.0.0-beta.1",
+			},
+			expBranchTags: []string{"v1.0.0", "v1.0.1", "v1.1.0-rc.1"},
+			expRelease:    "v1.1.0+b.2",
+			expPreRelease: "v2.0.0-beta.1",
+			expOnBranch:   "v1.1.0-rc.1",
+		},
+		{
+			ID:     testhelper.MkID("next"),
+			branch: "next",
+			expTags: []string{
+				"v1.0.0", "v1.0.1", "v1.1.0-rc.1",
+				"v1.1.0", "v1.1.0+b.1", "v1.1.0+b.2",
+				"v2.0.0-beta.1",
+			},
+			expBranchTags: []string{
+				"v1.0.0", "v1.1.0-rc.1",
+				"v1.1.0", "v1.1.0+b.1", "v1.1.0+b.2",
+				"v2.0.0-beta.1",
+			},
+			expRelease:    "v1.1.0+b.2",
+			expPreRelease: "v2.0.0-beta.1",
+			expOnBranch:   "v2.0.0-beta.1",
+		},
+		{
+			ID:            testhelper.MkID("prefix, main"),
+			prefix:        "mymod/",
+			branch:        "main",
+			expTags:       []string{"mymod/v0.1.0", "mymod/v0.2.0-alpha.1"},
+			expBranchTags: []string{"mymod/v0.1.0", "mymod/v0.2.0-alpha.1"},
+			expRelease:    "mymod/v0.1.0",
+			expPreRelease: "mymod/v0.2.0-alpha.1",
+			expOnBranch:   "mymod/v0.2.0-alpha.1",
+		},
+		{
+			ID:            testhelper.MkID("prefix, maint"),
+			prefix:        "mymod/",
+			branch:        "maint",
+			expTags:       []string{"mymod/v0.1.0", "mymod/v0.2.0-alpha.1"},
+			expBranchTags: []string{"mymod/v0.1.0"},
+			expRelease:    "mymod/v0.1.0",
+			expPreRelease: "mymod/v0.2.0-alpha.1",
+			expOnBranch:   "mymod/v0.1.0",
+		},
+		{
+			ID:            testhelper.MkID("prefix, none match"),
+			prefix:        "other/",
+			branch:        "main",
+			expTags:       []string{},
+			expBranchTags: []string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		r.git("checkout", "--quiet", tc.branch)
+
+		repo, err := gittag.NewRepo(r.dir, tc.prefix)
+		if err != nil {
+			t.Fatal(tc.IDStr(), ": unexpected error: ", err)
+		}
+
+		tags, err := repo.Tags()
+		if err != nil {
+			t.Fatal(tc.IDStr(), ": unexpected error: ", err)
+		}
+
+		testhelper.DiffStringSlice(t, tc.IDStr(), "tags",
+			tagNames(tags), tc.expTags)
+
+		branchTags, err := repo.BranchTags()
+		if err != nil {
+			t.Fatal(tc.IDStr(), ": unexpected error: ", err)
+		}
+
+		testhelper.DiffStringSlice(t, tc.IDStr(), "branch tags",
+			tagNames(branchTags), tc.expBranchTags)
+
+		l, err := repo.Latest()
+		if err != nil {
+			t.Fatal(tc.IDStr(), ": unexpected error: ", err)
+		}
+
+		testhelper.DiffString(t, tc.IDStr(), "latest release",
+			latestName(l.Release), tc.expRelease)
+		testhelper.DiffString(t, tc.IDStr(), "latest pre-release",
+			latestName(l.PreRelease), tc.expPreRelease)
+		testhelper.DiffString(t, tc.IDStr(), "latest on branch",
+			latestName(l.OnBranch), tc.expOnBranch)
+	}
+
+	repo, err := gittag.NewRepo(r.dir, "")
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+
+	tags, err := repo.Tags()
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+
+	for _, tag := range tags {
+		if tag.Name == "v1.1.0-rc.1" {
+			testhelper.DiffString(t, "annotated tag", "commit",
+				tag.Commit, commitA)
+		}
+	}
+}
+
+func TestEmptyRepo(t *testing.T) {
+	r := newTestRepo(t)
+
+	repo, err := gittag.NewRepo(r.dir, "")
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+
+	l, err := repo.Latest()
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+
+	if l.Release != nil || l.PreRelease != nil || l.OnBranch != nil {
+		t.Errorf("no tags were expected in an empty repository: %v", l)
+	}
+
+	branchTags, err := repo.BranchTags()
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+
+	testhelper.DiffInt(t, "empty repo", "branch tags", len(branchTags), 0)
+
+	next := l.Next((*semver.SV).IncrMinor)
+	testhelper.DiffString(t, "empty repo", "next version",
+		next.String(), "v0.1.0")
+}
+
+func TestNotARepo(t *testing.T) {
+	newTestRepo(t)
+
+	dir := t.TempDir()
+
+	tc := struct {
+		testhelper.ID
+		testhelper.ExpErr
+	}{
+		ID:     testhelper.MkID("not a repo"),
+		ExpErr: testhelper.MkExpErr("is not in a git repository"),
+	}
+
+	_, err := gittag.NewRepo(dir, "")
+	testhelper.CheckExpErr(t, err, tc)
+}
+
+func TestNext(t *testing.T) {
+	r, _ := buildRepo(t)
+
+	testCases := []struct {
+		testhelper.ID
+		branch     string
+		incr       func(*semver.SV)
+		expNext    string
+		expTagName string
+	}{
+		{
+			ID:         testhelper.MkID("main, minor"),
+			branch:     "main",
+			incr:       (*semver.SV).IncrMinor,
+			expNext:    "v1.2.0",
+			expTagName: "v1.2.0",
+		},
+		{
+			ID:     testhelper.MkID("next, release"),
+			branch: "next",
+			incr: func(sv *semver.SV) {
+				if err := sv.Release(); err != nil {
+					panic(err)
+				}
+			},
+			expNext:    "v2.0.0",
+			expTagName: "v2.0.0",
+		},
+		{
+			ID:     testhelper.MkID("maint, prerelease"),
+			branch: "maint",
+			incr: func(sv *semver.SV) {
+				if err := sv.IncrPreRelease("rc"); err != nil {
+					panic(err)
+				}
+			},
+			expNext:    "v1.1.0-rc.2",
+			expTagName: "v1.1.0-rc.2",
+		},
+	}
+
+	for _, tc := range testCases {
+		r.git("checkout", "--quiet", tc.branch)
+
+		repo, err := gittag.NewRepo(r.dir, "")
+		if err != nil {
+			t.Fatal(tc.IDStr(), ": unexpected error: ", err)
+		}
+
+		l, err := repo.Latest()
+		if err != nil {
+			t.Fatal(tc.IDStr(), ": unexpected error: ", err)
+		}
+
+		next := l.Next(tc.incr)
+		testhelper.DiffString(t, tc.IDStr(), "next version",
+			next.String(), tc.expNext)
+		testhelper.DiffString(t, tc.IDStr(), "tag name",
+			repo.TagName(next), tc.expTagName)
+	}
+}
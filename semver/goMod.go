@@ -0,0 +1,294 @@
+package semver
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	goModIncompatible  = "incompatible"
+	pseudoTimeFormat   = "20060102150405"
+	pseudoTimeSep      = "-"
+	pseudoBasePreRelID = "0"
+)
+
+// PseudoForm identifies which of the three forms of Go module
+// pseudo-version an SV has, if any
+type PseudoForm int
+
+const (
+	// NotPseudo indicates that the version is not a pseudo-version
+	NotPseudo PseudoForm = iota
+	// PseudoNoBase indicates a pseudo-version with no earlier tagged
+	// version, of the form vX.0.0-yyyymmddhhmmss-abcdefabcdef
+	PseudoNoBase
+	// PseudoPreRelBase indicates a pseudo-version following a pre-release
+	// version, vX.Y.Z-pre, of the form
+	// vX.Y.Z-pre.0.yyyymmddhhmmss-abcdefabcdef
+	PseudoPreRelBase
+	// PseudoReleaseBase indicates a pseudo-version following a release
+	// version, vX.Y.Z, of the form vX.Y.(Z+1)-0.yyyymmddhhmmss-abcdefabcdef.
+	// The go command also accepts this form with a patch version of 0,
+	// vX.Y.0-0.yyyymmddhhmmss-abcdefabcdef, though there is then no valid
+	// base version.
+	PseudoReleaseBase
+)
+
+// String returns a description of the pseudo-version form
+func (pf PseudoForm) String() string {
+	switch pf {
+	case NotPseudo:
+		return "not a pseudo-version"
+	case PseudoNoBase:
+		return "pseudo-version with no base version"
+	case PseudoPreRelBase:
+		return "pseudo-version with a pre-release base version"
+	case PseudoReleaseBase:
+		return "pseudo-version with a release base version"
+	}
+
+	return fmt.Sprintf("PseudoForm(%d)", int(pf))
+}
+
+// GoModVersion provides a view of an SV as a Go module version. It allows
+// you to recognise pseudo-versions and to extract their parts and to see if
+// the version is marked as "+incompatible".
+type GoModVersion struct {
+	sv *SV
+}
+
+// NewGoModVersion returns a GoModVersion view of the SV
+func NewGoModVersion(sv *SV) GoModVersion {
+	return GoModVersion{sv: sv}
+}
+
+// ParseGoModVersion parses the string as an SV (see ParseSV) and returns a
+// GoModVersion view of it. Go module versions must have a leading 'v'.
+func ParseGoModVersion(s string) (GoModVersion, error) {
+	sv, err := ParseSV(s)
+	if err != nil {
+		return GoModVersion{}, err
+	}
+
+	return GoModVersion{sv: sv}, nil
+}
+
+// SV returns the SV underlying the GoModVersion
+func (gmv GoModVersion) SV() *SV { return gmv.sv }
+
+// String returns a string representation of the GoModVersion
+func (gmv GoModVersion) String() string {
+	if gmv.sv == nil {
+		return ""
+	}
+
+	return gmv.sv.String()
+}
+
+// IsIncompatible returns true if the version has the "+incompatible" build
+// ID which the Go tools use to mark a version with a major version of 2 or
+// more for a module which has no go.mod file or whose module path lacks
+// the major version suffix.
+func (gmv GoModVersion) IsIncompatible() bool {
+	return gmv.sv != nil &&
+		len(gmv.sv.buildIDs) == 1 &&
+		gmv.sv.buildIDs[0] == goModIncompatible
+}
+
+// isPseudoTimeRev returns true if the ID has the form of the final part of
+// a pseudo-version: a 14 digit timestamp, a hyphen and a revision ID. As
+// with the go command, the timestamp need not be a valid time.
+func isPseudoTimeRev(id string) bool {
+	ts, rev, ok := strings.Cut(id, pseudoTimeSep)
+	if !ok || len(ts) != len(pseudoTimeFormat) || checkRev(rev) != nil {
+		return false
+	}
+
+	for _, r := range ts {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// checkRev returns a non-nil error if the revision ID is not a non-empty
+// string of letters and digits
+func checkRev(rev string) error {
+	if rev == "" {
+		return errors.New("the revision ID must not be empty")
+	}
+
+	for _, r := range rev {
+		if !(r >= '0' && r <= '9' ||
+			r >= 'a' && r <= 'z' ||
+			r >= 'A' && r <= 'Z') {
+			return fmt.Errorf(
+				"the revision ID %q must only have letters and digits", rev)
+		}
+	}
+
+	return nil
+}
+
+// PseudoForm returns the form of pseudo-version that the version has or
+// NotPseudo if it is not a pseudo-version. The versions recognised are the
+// same as those recognised by the go command (see
+// golang.org/x/mod/module.IsPseudoVersion).
+func (gmv GoModVersion) PseudoForm() PseudoForm {
+	if gmv.sv == nil {
+		return NotPseudo
+	}
+
+	ids := gmv.sv.preRelIDs
+	last := len(ids) - 1
+
+	if last < 0 || !isPseudoTimeRev(ids[last]) {
+		return NotPseudo
+	}
+
+	switch {
+	case len(ids) == 1:
+		if gmv.sv.minor.isZero() && gmv.sv.patch.isZero() {
+			return PseudoNoBase
+		}
+	case len(ids) == 2:
+		if ids[0] == pseudoBasePreRelID {
+			return PseudoReleaseBase
+		}
+	default:
+		if ids[last-1] == pseudoBasePreRelID {
+			return PseudoPreRelBase
+		}
+	}
+
+	return NotPseudo
+}
+
+// IsPseudo returns true if the version is a pseudo-version
+func (gmv GoModVersion) IsPseudo() bool {
+	return gmv.PseudoForm() != NotPseudo
+}
+
+// pseudoTimeRev returns the timestamp and revision parts of the
+// pseudo-version. It returns an error if it is not a pseudo-version
+func (gmv GoModVersion) pseudoTimeRev() (string, string, error) {
+	if !gmv.IsPseudo() {
+		return "", "", fmt.Errorf("%s is not a pseudo-version", gmv)
+	}
+
+	ts, rev, _ := strings.Cut(gmv.sv.preRelIDs[len(gmv.sv.preRelIDs)-1],
+		pseudoTimeSep)
+
+	return ts, rev, nil
+}
+
+// Time returns the UTC time recorded in the pseudo-version. It returns an
+// error if it is not a pseudo-version or if the timestamp is not a valid
+// time
+func (gmv GoModVersion) Time() (time.Time, error) {
+	ts, _, err := gmv.pseudoTimeRev()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	t, err := time.Parse(pseudoTimeFormat, ts)
+	if err != nil {
+		return time.Time{},
+			fmt.Errorf("%s has a bad timestamp: %q", gmv, ts)
+	}
+
+	return t, nil
+}
+
+// Rev returns the revision ID (the commit hash prefix) recorded in the
+// pseudo-version. It returns an error if it is not a pseudo-version
+func (gmv GoModVersion) Rev() (string, error) {
+	_, rev, err := gmv.pseudoTimeRev()
+
+	return rev, err
+}
+
+// Base returns the version on which the pseudo-version is based, that is,
+// the most recent tagged version before the revision. It returns nil if
+// the pseudo-version has no base version and an error if it is not a
+// pseudo-version, if it has the release base form with a patch version of
+// 0 or if it has no base version but has build IDs. Any build IDs (such as
+// "+incompatible") are kept.
+func (gmv GoModVersion) Base() (*SV, error) {
+	var base *SV
+
+	switch gmv.PseudoForm() {
+	case NotPseudo:
+		return nil, fmt.Errorf("%s is not a pseudo-version", gmv)
+	case PseudoNoBase:
+		if gmv.sv.HasBuildIDs() {
+			return nil, fmt.Errorf(
+				"%s lacks a base version, but has build metadata", gmv)
+		}
+
+		return nil, nil
+	case PseudoPreRelBase:
+		base = &SV{}
+		gmv.sv.CopyInto(base)
+		base.preRelIDs = base.preRelIDs[:len(base.preRelIDs)-2]
+	case PseudoReleaseBase:
+		if gmv.sv.patch.isZero() {
+			return nil, fmt.Errorf("%s has no base version:"+
+				" it would have a negative patch number", gmv)
+		}
+
+		base = &SV{}
+		gmv.sv.CopyInto(base)
+		base.patch = base.patch.decr()
+		base.ClearPreRelIDs()
+	}
+
+	return base, nil
+}
+
+// NewPseudoVersion returns a pseudo-version for the revision made at time
+// t. The base is the most recent tagged version before the revision; if
+// there is no such version it should be nil and the major version is used
+// to construct the pseudo-version. If the base is not nil then the major
+// version must match that of the base. Any build IDs on the base are
+// kept. The time is converted to UTC. The Go tools use a revision ID of 12
+// characters; the revision ID is used as given.
+func NewPseudoVersion(major int, base *SV, t time.Time, rev string,
+) (GoModVersion, error) {
+	if err := checkRev(rev); err != nil {
+		return GoModVersion{}, err
+	}
+
+	timeRev := t.UTC().Format(pseudoTimeFormat) + pseudoTimeSep + rev
+
+	if base == nil {
+		sv, err := NewSV(major, 0, 0, []string{timeRev}, nil)
+		if err != nil {
+			return GoModVersion{}, err
+		}
+
+		return GoModVersion{sv: sv}, nil
+	}
+
+	if base.major.cmpInt(major) != 0 {
+		return GoModVersion{}, fmt.Errorf(
+			"the major version (%d) does not match the base version (%s)",
+			major, base)
+	}
+
+	sv := &SV{}
+	base.CopyInto(sv)
+
+	if sv.HasPreRelIDs() {
+		sv.preRelIDs = append(sv.preRelIDs, pseudoBasePreRelID, timeRev)
+	} else {
+		sv.patch = sv.patch.incr()
+		sv.preRelIDs = []string{pseudoBasePreRelID, timeRev}
+	}
+
+	return GoModVersion{sv: sv}, nil
+}
@@ -0,0 +1,218 @@
+package semver
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	gopkgInPrefix    = "gopkg.in/"
+	gopkgInUnstable  = "-unstable"
+	gopkgInMajorPfx  = ".v"
+	modPathMajorPfx  = "/v"
+	modPathMinSfxVsn = 2
+)
+
+// isDigit returns true if the byte is a decimal digit
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
+
+// SplitModulePath splits the Go module path into a prefix and a major
+// version suffix. The suffix has the form "/vN" where N is at least 2 (for
+// instance "github.com/nickwells/semver.mod/v3" has the suffix "/v3") or,
+// for paths starting "gopkg.in/", the form ".vN" (for instance
+// "gopkg.in/yaml.v2" has the suffix ".v2"). If there is no suffix then the
+// prefix is the whole path and the suffix is empty. The boolean return value
+// is false if the path has a badly formed suffix, such as "/v1" or "/v02".
+func SplitModulePath(path string) (prefix, pathMajor string, ok bool) {
+	if strings.HasPrefix(path, gopkgInPrefix) {
+		return splitGopkgInPath(path)
+	}
+
+	i := len(path)
+	hasDot := false
+
+	for i > 0 && (isDigit(path[i-1]) || path[i-1] == '.') {
+		if path[i-1] == '.' {
+			hasDot = true
+		}
+
+		i--
+	}
+
+	if i <= 1 || i == len(path) || path[i-2:i] != modPathMajorPfx {
+		return path, "", true
+	}
+
+	prefix, pathMajor = path[:i-2], path[i-2:]
+	if hasDot || pathMajor[2] == '0' || pathMajor == modPathMajorPfx+"1" {
+		return path, "", false
+	}
+
+	return prefix, pathMajor, true
+}
+
+// splitGopkgInPath splits a gopkg.in module path into a prefix and a major
+// version suffix of the form ".vN" (optionally followed by "-unstable"). A
+// gopkg.in path must always have a major version suffix. As with the go
+// command, a major version of 0 is only allowed as ".v0".
+func splitGopkgInPath(path string) (prefix, pathMajor string, ok bool) {
+	i := len(strings.TrimSuffix(path, gopkgInUnstable))
+	end := i
+
+	for i > 0 && isDigit(path[i-1]) {
+		i--
+	}
+
+	if i <= 1 || i == end || path[i-2:i] != gopkgInMajorPfx {
+		return path, "", false
+	}
+
+	prefix, pathMajor = path[:i-2], path[i-2:]
+	if pathMajor[2] == '0' && pathMajor != gopkgInMajorPfx+"0" {
+		return path, "", false
+	}
+
+	return prefix, pathMajor, true
+}
+
+// pathMajorVsn returns the major version number given by the module path
+// suffix. An empty suffix returns an empty string.
+func pathMajorVsn(pathMajor string) string {
+	if pathMajor == "" {
+		return ""
+	}
+
+	return strings.TrimSuffix(pathMajor[2:], gopkgInUnstable)
+}
+
+// CheckModulePath checks that the Go module path is consistent with the
+// version according to the Go rules for major version suffixes. It returns
+// an error if not. The rules are:
+//
+//   - a version with major version 0 or 1 must have no suffix
+//   - a version with major version N (2 or more) must have the suffix "/vN"
+//     unless it has the "+incompatible" build ID in which case it must have
+//     no suffix
+//   - a gopkg.in path must always have the suffix ".vN" matching the major
+//     version (pseudo-versions with no base version, v0.0.0-..., may have
+//     the suffix ".v1")
+func CheckModulePath(path string, sv *SV) error {
+	prefix, pathMajor, ok := SplitModulePath(path)
+	if !ok {
+		return fmt.Errorf("the module path %q has a bad major version suffix",
+			path)
+	}
+
+	gmv := NewGoModVersion(sv)
+
+	if strings.HasPrefix(prefix, gopkgInPrefix) {
+		if pathMajorVsn(pathMajor) == sv.major.String() ||
+			pathMajor == gopkgInMajorPfx+"1" &&
+				gmv.PseudoForm() == PseudoNoBase && sv.major.isZero() {
+			return nil
+		}
+
+		return fmt.Errorf(
+			"the module path %q has the suffix %q but the version is %s",
+			path, pathMajor, sv)
+	}
+
+	if gmv.IsIncompatible() {
+		if pathMajor != "" {
+			return fmt.Errorf(
+				"the module path %q has a major version suffix"+
+					" but the version (%s) is marked as incompatible",
+				path, sv)
+		}
+
+		if sv.major.cmpInt(modPathMinSfxVsn) < 0 {
+			return fmt.Errorf(
+				"the version (%s) is marked as incompatible"+
+					" but the major version is less than %d",
+				sv, modPathMinSfxVsn)
+		}
+
+		return nil
+	}
+
+	if sv.major.cmpInt(modPathMinSfxVsn) < 0 {
+		if pathMajor != "" {
+			return fmt.Errorf(
+				"the module path %q has the suffix %q"+
+					" but the version (%s) should have no suffix",
+				path, pathMajor, sv)
+		}
+
+		return nil
+	}
+
+	if pathMajorVsn(pathMajor) != sv.major.String() {
+		return fmt.Errorf(
+			"the module path %q should have the suffix %q"+
+				" for the version (%s)",
+			path, modPathMajorPfx+sv.major.String(), sv)
+	}
+
+	return nil
+}
+
+// ModulePathForMajor returns the Go module path with its major version
+// suffix changed to match the major version. It returns an error if the
+// path has a badly formed suffix.
+func ModulePathForMajor(path string, major int) (string, error) {
+	if major < 0 {
+		return "", fmt.Errorf("bad major version: %d - it must be %s",
+			major, GoodVsnNumDesc)
+	}
+
+	return modulePathForMajor(path, intVNum(major))
+}
+
+// modulePathForMajor returns the Go module path with its major version
+// suffix changed to match the major version
+func modulePathForMajor(path string, major vNum) (string, error) {
+	prefix, _, ok := SplitModulePath(path)
+	if !ok {
+		return "", fmt.Errorf(
+			"the module path %q has a bad major version suffix", path)
+	}
+
+	if strings.HasPrefix(prefix, gopkgInPrefix) {
+		return prefix + gopkgInMajorPfx + major.String(), nil
+	}
+
+	if major.cmpInt(modPathMinSfxVsn) < 0 {
+		return prefix, nil
+	}
+
+	return prefix + modPathMajorPfx + major.String(), nil
+}
+
+// ModulePathForIncrMajor checks that the Go module path is consistent with
+// the version and then returns the new version that IncrMajor would give
+// and the module path that the new version would need. Note that any
+// "+incompatible" build ID is removed from the new version as the new
+// module path will have a major version suffix. The version passed is not
+// changed.
+func ModulePathForIncrMajor(path string, sv *SV) (string, *SV, error) {
+	if err := CheckModulePath(path, sv); err != nil {
+		return "", nil, err
+	}
+
+	next := &SV{}
+	sv.CopyInto(next)
+	next.IncrMajor()
+
+	if NewGoModVersion(next).IsIncompatible() {
+		next.ClearBuildIDs()
+	}
+
+	newPath, err := modulePathForMajor(path, next.major)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return newPath, next, nil
+}
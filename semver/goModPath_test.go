@@ -0,0 +1,309 @@
+package semver_test
+
+import (
+	"testing"
+
+	"github.com/nickwells/semver.mod/v3/semver"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestSplitModulePath(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		path         string
+		expPrefix    string
+		expPathMajor string
+		expOK        bool
+	}{
+		{
+			ID:        testhelper.MkID("no suffix"),
+			path:      "github.com/nickwells/semver.mod",
+			expPrefix: "github.com/nickwells/semver.mod",
+			expOK:     true,
+		},
+		{
+			ID:           testhelper.MkID("v3 suffix"),
+			path:         "github.com/nickwells/semver.mod/v3",
+			expPrefix:    "github.com/nickwells/semver.mod",
+			expPathMajor: "/v3",
+			expOK:        true,
+		},
+		{
+			ID:           testhelper.MkID("v10 suffix"),
+			path:         "example.com/m/v10",
+			expPrefix:    "example.com/m",
+			expPathMajor: "/v10",
+			expOK:        true,
+		},
+		{
+			ID:        testhelper.MkID("bad - v1 suffix"),
+			path:      "example.com/m/v1",
+			expPrefix: "example.com/m/v1",
+		},
+		{
+			ID:        testhelper.MkID("bad - v0 suffix"),
+			path:      "example.com/m/v0",
+			expPrefix: "example.com/m/v0",
+		},
+		{
+			ID:        testhelper.MkID("bad - leading zero"),
+			path:      "example.com/m/v02",
+			expPrefix: "example.com/m/v02",
+		},
+		{
+			ID:        testhelper.MkID("bad - dotted suffix"),
+			path:      "example.com/m/v2.1",
+			expPrefix: "example.com/m/v2.1",
+		},
+		{
+			ID:        testhelper.MkID("not a suffix - trailing digits"),
+			path:      "example.com/m2",
+			expPrefix: "example.com/m2",
+			expOK:     true,
+		},
+		{
+			ID:           testhelper.MkID("gopkg.in"),
+			path:         "gopkg.in/yaml.v2",
+			expPrefix:    "gopkg.in/yaml",
+			expPathMajor: ".v2",
+			expOK:        true,
+		},
+		{
+			ID:           testhelper.MkID("gopkg.in - v0"),
+			path:         "gopkg.in/yaml.v0",
+			expPrefix:    "gopkg.in/yaml",
+			expPathMajor: ".v0",
+			expOK:        true,
+		},
+		{
+			ID:           testhelper.MkID("gopkg.in - unstable"),
+			path:         "gopkg.in/yaml.v3-unstable",
+			expPrefix:    "gopkg.in/yaml",
+			expPathMajor: ".v3-unstable",
+			expOK:        true,
+		},
+		{
+			ID:        testhelper.MkID("bad - gopkg.in with no suffix"),
+			path:      "gopkg.in/yaml",
+			expPrefix: "gopkg.in/yaml",
+		},
+		{
+			ID:        testhelper.MkID("bad - gopkg.in with a leading zero"),
+			path:      "gopkg.in/yaml.v02",
+			expPrefix: "gopkg.in/yaml.v02",
+		},
+		{
+			ID:        testhelper.MkID("bad - gopkg.in v0 unstable"),
+			path:      "gopkg.in/yaml.v0-unstable",
+			expPrefix: "gopkg.in/yaml.v0-unstable",
+		},
+	}
+
+	for _, tc := range testCases {
+		prefix, pathMajor, ok := semver.SplitModulePath(tc.path)
+		testhelper.DiffString(t, tc.IDStr(), "prefix", prefix, tc.expPrefix)
+		testhelper.DiffString(t, tc.IDStr(), "path major",
+			pathMajor, tc.expPathMajor)
+		testhelper.DiffBool(t, tc.IDStr(), "ok", ok, tc.expOK)
+	}
+}
+
+func TestCheckModulePath(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		path  string
+		svStr string
+	}{
+		{
+			ID:    testhelper.MkID("v3 suffix, v3 version"),
+			path:  "github.com/nickwells/semver.mod/v3",
+			svStr: "v3.1.2",
+		},
+		{
+			ID:    testhelper.MkID("v3 suffix, v4 version"),
+			path:  "github.com/nickwells/semver.mod/v3",
+			svStr: "v4.0.0",
+			ExpErr: testhelper.MkExpErr(
+				`the module path "github.com/nickwells/semver.mod/v3"`+
+					` should have the suffix "/v4"`,
+				"(v4.0.0)"),
+		},
+		{
+			ID:    testhelper.MkID("no suffix, v0 version"),
+			path:  "example.com/m",
+			svStr: "v0.3.0",
+		},
+		{
+			ID:    testhelper.MkID("no suffix, v1 version"),
+			path:  "example.com/m",
+			svStr: "v1.3.0",
+		},
+		{
+			ID:    testhelper.MkID("no suffix, v2 version"),
+			path:  "example.com/m",
+			svStr: "v2.0.0",
+			ExpErr: testhelper.MkExpErr(
+				`the module path "example.com/m" should have the suffix "/v2"`),
+		},
+		{
+			ID:    testhelper.MkID("v2 suffix, v1 version"),
+			path:  "example.com/m/v2",
+			svStr: "v1.0.0",
+			ExpErr: testhelper.MkExpErr(
+				`the module path "example.com/m/v2" has the suffix "/v2"`,
+				"should have no suffix"),
+		},
+		{
+			ID:    testhelper.MkID("bad suffix"),
+			path:  "example.com/m/v1",
+			svStr: "v1.0.0",
+			ExpErr: testhelper.MkExpErr(
+				`the module path "example.com/m/v1"` +
+					` has a bad major version suffix`),
+		},
+		{
+			ID:    testhelper.MkID("no suffix, incompatible"),
+			path:  "example.com/m",
+			svStr: "v2.0.0+incompatible",
+		},
+		{
+			ID:    testhelper.MkID("suffix, incompatible"),
+			path:  "example.com/m/v2",
+			svStr: "v2.0.0+incompatible",
+			ExpErr: testhelper.MkExpErr(
+				`the module path "example.com/m/v2" has a major version suffix`,
+				"is marked as incompatible"),
+		},
+		{
+			ID:    testhelper.MkID("incompatible, v1 version"),
+			path:  "example.com/m",
+			svStr: "v1.0.0+incompatible",
+			ExpErr: testhelper.MkExpErr(
+				"the version (v1.0.0+incompatible) is marked as incompatible",
+				"less than 2"),
+		},
+		{
+			ID:    testhelper.MkID("v3 suffix, v3 pseudo-version"),
+			path:  "example.com/m/v3",
+			svStr: "v3.0.0-20260410095643-746e56fc9e2f",
+		},
+		{
+			ID:    testhelper.MkID("gopkg.in, matching version"),
+			path:  "gopkg.in/yaml.v2",
+			svStr: "v2.4.0",
+		},
+		{
+			ID:    testhelper.MkID("gopkg.in v0, v0 version"),
+			path:  "gopkg.in/yaml.v0",
+			svStr: "v0.4.0",
+		},
+		{
+			ID:    testhelper.MkID("gopkg.in v1, v1 version"),
+			path:  "gopkg.in/yaml.v1",
+			svStr: "v1.4.0",
+		},
+		{
+			ID:    testhelper.MkID("gopkg.in v1, v0 pseudo-version"),
+			path:  "gopkg.in/yaml.v1",
+			svStr: "v0.0.0-20260410095643-746e56fc9e2f",
+		},
+		{
+			ID:    testhelper.MkID("gopkg.in v1, v0 version"),
+			path:  "gopkg.in/yaml.v1",
+			svStr: "v0.4.0",
+			ExpErr: testhelper.MkExpErr(
+				`the module path "gopkg.in/yaml.v1" has the suffix ".v1"`,
+				"but the version is v0.4.0"),
+		},
+		{
+			ID:    testhelper.MkID("gopkg.in, mismatched version"),
+			path:  "gopkg.in/yaml.v2",
+			svStr: "v3.0.0",
+			ExpErr: testhelper.MkExpErr(
+				`the module path "gopkg.in/yaml.v2" has the suffix ".v2"`,
+				"but the version is v3.0.0"),
+		},
+	}
+
+	for _, tc := range testCases {
+		sv, err := semver.ParseSV(tc.svStr)
+		if err != nil {
+			t.Fatal("Couldn't parse the semver: ", err)
+		}
+
+		err = semver.CheckModulePath(tc.path, sv)
+		testhelper.CheckExpErr(t, err, tc)
+	}
+}
+
+func TestModulePathForIncrMajor(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		path       string
+		svStr      string
+		expPath    string
+		expNextStr string
+	}{
+		{
+			ID:         testhelper.MkID("v0 to v1"),
+			path:       "example.com/m",
+			svStr:      "v0.3.0",
+			expPath:    "example.com/m",
+			expNextStr: "v1.0.0",
+		},
+		{
+			ID:         testhelper.MkID("v1 to v2"),
+			path:       "example.com/m",
+			svStr:      "v1.3.0-rc.1",
+			expPath:    "example.com/m/v2",
+			expNextStr: "v2.0.0",
+		},
+		{
+			ID:         testhelper.MkID("v3 to v4"),
+			path:       "github.com/nickwells/semver.mod/v3",
+			svStr:      "v3.1.2",
+			expPath:    "github.com/nickwells/semver.mod/v4",
+			expNextStr: "v4.0.0",
+		},
+		{
+			ID:         testhelper.MkID("incompatible"),
+			path:       "example.com/m",
+			svStr:      "v2.1.0+incompatible",
+			expPath:    "example.com/m/v3",
+			expNextStr: "v3.0.0",
+		},
+		{
+			ID:         testhelper.MkID("gopkg.in"),
+			path:       "gopkg.in/yaml.v2",
+			svStr:      "v2.4.0",
+			expPath:    "gopkg.in/yaml.v3",
+			expNextStr: "v3.0.0",
+		},
+		{
+			ID:    testhelper.MkID("bad - path does not match the version"),
+			path:  "example.com/m",
+			svStr: "v2.1.0",
+			ExpErr: testhelper.MkExpErr(
+				`the module path "example.com/m" should have the suffix "/v2"`),
+		},
+	}
+
+	for _, tc := range testCases {
+		sv, err := semver.ParseSV(tc.svStr)
+		if err != nil {
+			t.Fatal("Couldn't parse the semver: ", err)
+		}
+
+		path, next, err := semver.ModulePathForIncrMajor(tc.path, sv)
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			testhelper.DiffString(t, tc.IDStr(), "module path",
+				path, tc.expPath)
+			testhelper.DiffString(t, tc.IDStr(), "next version",
+				next.String(), tc.expNextStr)
+			testhelper.DiffString(t, tc.IDStr(), "original version",
+				sv.String(), tc.svStr)
+		}
+	}
+}
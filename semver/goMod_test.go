@@ -0,0 +1,290 @@
+package semver_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nickwells/semver.mod/v3/semver"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestGoModVersion(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		svStr           string
+		expForm         semver.PseudoForm
+		expIncompatible bool
+		expBase         string
+		expBaseErr      bool
+		expTime         time.Time
+		expTimeErr      bool
+		expRev          string
+	}{
+		{
+			ID:      testhelper.MkID("not pseudo - release"),
+			svStr:   "v1.2.3",
+			expForm: semver.NotPseudo,
+		},
+		{
+			ID:      testhelper.MkID("not pseudo - pre-release"),
+			svStr:   "v1.2.3-rc.1",
+			expForm: semver.NotPseudo,
+		},
+		{
+			ID:      testhelper.MkID("not pseudo - short timestamp"),
+			svStr:   "v0.0.0-2026041009564-746e56fc9e2f",
+			expForm: semver.NotPseudo,
+		},
+		{
+			ID:      testhelper.MkID("not pseudo - timestamp not digits"),
+			svStr:   "v0.0.0-2026041009564x-746e56fc9e2f",
+			expForm: semver.NotPseudo,
+		},
+		{
+			ID:      testhelper.MkID("not pseudo - no base but non-zero minor"),
+			svStr:   "v0.1.0-20260410095643-746e56fc9e2f",
+			expForm: semver.NotPseudo,
+		},
+		{
+			ID:              testhelper.MkID("not pseudo - incompatible"),
+			svStr:           "v2.0.0+incompatible",
+			expForm:         semver.NotPseudo,
+			expIncompatible: true,
+		},
+		{
+			ID:      testhelper.MkID("pseudo - no base"),
+			svStr:   "v0.0.0-20260410095643-746e56fc9e2f",
+			expForm: semver.PseudoNoBase,
+			expBase: "nil",
+			expTime: time.Date(2026, 4, 10, 9, 56, 43, 0, time.UTC),
+			expRev:  "746e56fc9e2f",
+		},
+		{
+			ID:         testhelper.MkID("pseudo - bad timestamp"),
+			svStr:      "v0.0.0-20261310095643-746e56fc9e2f",
+			expForm:    semver.PseudoNoBase,
+			expBase:    "nil",
+			expTimeErr: true,
+			expRev:     "746e56fc9e2f",
+		},
+		{
+			ID:              testhelper.MkID("pseudo - no base, incompatible"),
+			svStr:           "v0.0.0-20191109021931-daa7c04131f5+incompatible",
+			expForm:         semver.PseudoNoBase,
+			expIncompatible: true,
+			expBaseErr:      true,
+			expTime:         time.Date(2019, 11, 9, 2, 19, 31, 0, time.UTC),
+			expRev:          "daa7c04131f5",
+		},
+		{
+			ID:              testhelper.MkID("pseudo - no base, v2, incompatible"),
+			svStr:           "v2.0.0-20191109021931-daa7c04131f5+incompatible",
+			expForm:         semver.PseudoNoBase,
+			expIncompatible: true,
+			expBaseErr:      true,
+			expTime:         time.Date(2019, 11, 9, 2, 19, 31, 0, time.UTC),
+			expRev:          "daa7c04131f5",
+		},
+		{
+			ID:         testhelper.MkID("pseudo - release base with patch 0"),
+			svStr:      "v1.2.0-0.20260410095643-746e56fc9e2f",
+			expForm:    semver.PseudoReleaseBase,
+			expBaseErr: true,
+			expTime:    time.Date(2026, 4, 10, 9, 56, 43, 0, time.UTC),
+			expRev:     "746e56fc9e2f",
+		},
+		{
+			ID:         testhelper.MkID("pseudo - release base, v0.0.0"),
+			svStr:      "v0.0.0-0.20260410095643-746e56fc9e2f",
+			expForm:    semver.PseudoReleaseBase,
+			expBaseErr: true,
+			expTime:    time.Date(2026, 4, 10, 9, 56, 43, 0, time.UTC),
+			expRev:     "746e56fc9e2f",
+		},
+		{
+			ID:      testhelper.MkID("pseudo - pre-release base"),
+			svStr:   "v1.2.3-pre.1.0.20191109021931-daa7c04131f5",
+			expForm: semver.PseudoPreRelBase,
+			expBase: "v1.2.3-pre.1",
+			expTime: time.Date(2019, 11, 9, 2, 19, 31, 0, time.UTC),
+			expRev:  "daa7c04131f5",
+		},
+		{
+			ID:      testhelper.MkID("pseudo - release base"),
+			svStr:   "v1.2.4-0.20191109021931-daa7c04131f5",
+			expForm: semver.PseudoReleaseBase,
+			expBase: "v1.2.3",
+			expTime: time.Date(2019, 11, 9, 2, 19, 31, 0, time.UTC),
+			expRev:  "daa7c04131f5",
+		},
+		{
+			ID:      testhelper.MkID("pseudo - release base with big patch"),
+			svStr:   "v1.2.100000000000000000000-0.20191109021931-daa7c04131f5",
+			expForm: semver.PseudoReleaseBase,
+			expBase: "v1.2.99999999999999999999",
+			expTime: time.Date(2019, 11, 9, 2, 19, 31, 0, time.UTC),
+			expRev:  "daa7c04131f5",
+		},
+		{
+			ID:              testhelper.MkID("pseudo - incompatible"),
+			svStr:           "v2.0.1-0.20191109021931-daa7c04131f5+incompatible",
+			expForm:         semver.PseudoReleaseBase,
+			expIncompatible: true,
+			expBase:         "v2.0.0+incompatible",
+			expTime:         time.Date(2019, 11, 9, 2, 19, 31, 0, time.UTC),
+			expRev:          "daa7c04131f5",
+		},
+	}
+
+	for _, tc := range testCases {
+		gmv, err := semver.ParseGoModVersion(tc.svStr)
+		if err != nil {
+			t.Fatal("Couldn't parse the semver: ", err)
+		}
+
+		testhelper.DiffString(t, tc.IDStr(), "pseudo form",
+			gmv.PseudoForm().String(), tc.expForm.String())
+		testhelper.DiffBool(t, tc.IDStr(), "is pseudo",
+			gmv.IsPseudo(), tc.expForm != semver.NotPseudo)
+		testhelper.DiffBool(t, tc.IDStr(), "is incompatible",
+			gmv.IsIncompatible(), tc.expIncompatible)
+
+		base, err := gmv.Base()
+		if tc.expForm == semver.NotPseudo {
+			if err == nil {
+				t.Log(tc.IDStr())
+				t.Errorf("\t: an error was expected from Base()")
+			}
+
+			continue
+		}
+
+		switch {
+		case err != nil && !tc.expBaseErr:
+			t.Log(tc.IDStr())
+			t.Errorf("\t: unexpected error from Base(): %s", err)
+		case err == nil && tc.expBaseErr:
+			t.Log(tc.IDStr())
+			t.Errorf("\t: an error was expected from Base()")
+		case err == nil:
+			testhelper.DiffString(t, tc.IDStr(), "base",
+				svStr(base), tc.expBase)
+		}
+
+		ts, err := gmv.Time()
+		switch {
+		case err != nil && !tc.expTimeErr:
+			t.Log(tc.IDStr())
+			t.Errorf("\t: unexpected error from Time(): %s", err)
+		case err == nil && tc.expTimeErr:
+			t.Log(tc.IDStr())
+			t.Errorf("\t: an error was expected from Time()")
+		case err == nil:
+			testhelper.DiffTime(t, tc.IDStr(), "time", ts, tc.expTime)
+		}
+
+		rev, err := gmv.Rev()
+		if err != nil {
+			t.Log(tc.IDStr())
+			t.Errorf("\t: unexpected error from Rev(): %s", err)
+		} else {
+			testhelper.DiffString(t, tc.IDStr(), "revision", rev, tc.expRev)
+		}
+	}
+}
+
+func TestNewPseudoVersion(t *testing.T) {
+	ts := time.Date(2019, 11, 9, 2, 19, 31, 0, time.UTC)
+	rev := "daa7c04131f5"
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		major  int
+		base   *semver.SV
+		ts     time.Time
+		rev    string
+		expStr string
+	}{
+		{
+			ID:     testhelper.MkID("no base"),
+			major:  2,
+			ts:     ts,
+			rev:    rev,
+			expStr: "v2.0.0-20191109021931-daa7c04131f5",
+		},
+		{
+			ID:     testhelper.MkID("no base - time is converted to UTC"),
+			major:  0,
+			ts:     ts.In(time.FixedZone("X", 3600)),
+			rev:    rev,
+			expStr: "v0.0.0-20191109021931-daa7c04131f5",
+		},
+		{
+			ID:     testhelper.MkID("pre-release base"),
+			major:  1,
+			base:   semver.NewSVOrPanic(1, 2, 3, []string{"pre", "1"}, nil),
+			ts:     ts,
+			rev:    rev,
+			expStr: "v1.2.3-pre.1.0.20191109021931-daa7c04131f5",
+		},
+		{
+			ID:     testhelper.MkID("release base"),
+			major:  1,
+			base:   semver.NewSVOrPanic(1, 2, 3, nil, nil),
+			ts:     ts,
+			rev:    rev,
+			expStr: "v1.2.4-0.20191109021931-daa7c04131f5",
+		},
+		{
+			ID:    testhelper.MkID("release base - incompatible"),
+			major: 2,
+			base: semver.NewSVOrPanic(2, 0, 0,
+				nil, []string{"incompatible"}),
+			ts:     ts,
+			rev:    rev,
+			expStr: "v2.0.1-0.20191109021931-daa7c04131f5+incompatible",
+		},
+		{
+			ID:     testhelper.MkID("bad - major differs from the base"),
+			major:  2,
+			base:   semver.NewSVOrPanic(1, 2, 3, nil, nil),
+			ts:     ts,
+			rev:    rev,
+			ExpErr: testhelper.MkExpErr("the major version (2) does not match"),
+		},
+		{
+			ID:     testhelper.MkID("bad - empty revision"),
+			major:  2,
+			ts:     ts,
+			ExpErr: testhelper.MkExpErr("the revision ID must not be empty"),
+		},
+		{
+			ID:    testhelper.MkID("bad - bad revision"),
+			major: 2,
+			ts:    ts,
+			rev:   "abc-def",
+			ExpErr: testhelper.MkExpErr(
+				`the revision ID "abc-def" must only have letters and digits`),
+		},
+	}
+
+	for _, tc := range testCases {
+		gmv, err := semver.NewPseudoVersion(tc.major, tc.base, tc.ts, tc.rev)
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			testhelper.DiffString(t, tc.IDStr(), "pseudo-version",
+				gmv.String(), tc.expStr)
+			testhelper.DiffBool(t, tc.IDStr(), "is pseudo",
+				gmv.IsPseudo(), true)
+
+			base, err := gmv.Base()
+			if err != nil {
+				t.Log(tc.IDStr())
+				t.Errorf("\t: unexpected error from Base(): %s", err)
+			} else {
+				testhelper.DiffString(t, tc.IDStr(), "base",
+					svStr(base), svStr(tc.base))
+			}
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package semver
+
+import "strings"
+
+// svLayout records the values and positions of the parts of a well-formed
+// semantic version ID (without the leading 'v') as found by scanSV
+type svLayout struct {
+	vNums       [semverVsnPartCount]span
+	coreEnd     int
+	preRelEnd   int
+	preRelCount int
+	buildCount  int
+}
+
+// scanIDs scans the list of pre-release or build IDs starting at offset i
+// of the text. It returns the offset of the end of the list, the number of
+// IDs and false if any of the IDs is bad.
+func scanIDs[T byteSeq](text T, i int, isPreRel bool) (int, int, bool) {
+	count := 0
+
+	for {
+		s := span{start: i, end: scanWhile(text, i, isIDChar)}
+		if s.start == s.end {
+			return i, count, false
+		}
+
+		if isPreRel && isNumeric(text, s) && hasLeadingZero(text, s) {
+			return i, count, false
+		}
+
+		count++
+		i = s.end
+
+		if i >= len(text) || text[i] != semverPartSeparator[0] {
+			return i, count, true
+		}
+
+		i++
+	}
+}
+
+// scanSV checks that the whole of the text is a well-formed semantic
+// version ID without the leading 'v'. It returns the layout of the SV and
+// true if it is, false otherwise. It does not allocate.
+func scanSV[T byteSeq](text T) (svLayout, bool) {
+	var l svLayout
+
+	i := 0
+
+	for p := range semverVsnPartCount {
+		if p > 0 {
+			if i >= len(text) || text[i] != semverPartSeparator[0] {
+				return l, false
+			}
+
+			i++
+		}
+
+		s := span{start: i, end: scanWhile(text, i, isDigit)}
+		if s.start == s.end || hasLeadingZero(text, s) {
+			return l, false
+		}
+
+		l.vNums[p] = s
+		i = s.end
+	}
+
+	l.coreEnd = i
+
+	var ok bool
+
+	if i < len(text) && text[i] == semverPreRelIDsSeparator[0] {
+		i, l.preRelCount, ok = scanIDs(text, i+1, true)
+		if !ok {
+			return l, false
+		}
+	}
+
+	l.preRelEnd = i
+
+	if i < len(text) && text[i] == semverBuildIDsSeparator[0] {
+		i, l.buildCount, ok = scanIDs(text, i+1, false)
+		if !ok {
+			return l, false
+		}
+	}
+
+	return l, i == len(text)
+}
+
+// cutPrefix removes the leading 'v' from the text. It returns false if the
+// text does not start with a 'v'.
+func cutPrefix[T byteSeq](text T) (T, bool) {
+	if len(text) == 0 || text[0] != semverPrefix[0] {
+		return text, false
+	}
+
+	return text[len(semverPrefix):], true
+}
+
+// appendIDs appends the IDs in the string to the slice
+func appendIDs(ids []string, s string) []string {
+	for {
+		id, rest, found := strings.Cut(s, semverPartSeparator)
+		ids = append(ids, id)
+
+		if !found {
+			return ids
+		}
+
+		s = rest
+	}
+}
+
+// mkSV makes a new SV from the string using the layout found by scanSV. The
+// IDs in the SV refer to the string, which is not copied.
+func (l svLayout) mkSV(s string) *SV {
+	sv := &SV{hasBeenSet: true}
+
+	vNums := [semverVsnPartCount]*vNum{&sv.major, &sv.minor, &sv.patch}
+	for i, p := range vNums {
+		*p = vNum(s[l.vNums[i].start:l.vNums[i].end])
+	}
+
+	idCount := l.preRelCount + l.buildCount
+	if idCount == 0 {
+		return sv
+	}
+
+	ids := make([]string, 0, idCount)
+
+	if l.preRelCount > 0 {
+		preRelStart := l.coreEnd + len(semverPreRelIDsSeparator)
+		ids = appendIDs(ids, s[preRelStart:l.preRelEnd])
+		sv.preRelIDs = ids[:l.preRelCount:l.preRelCount]
+	}
+
+	if l.buildCount > 0 {
+		ids = appendIDs(ids, s[l.preRelEnd+len(semverBuildIDsSeparator):])
+		sv.buildIDs = ids[l.preRelCount:]
+	}
+
+	return sv
+}
+
+// IsValid returns true if the string is a well-formed semantic version ID
+// with the leading 'v'. It gives the same result as checking for an error
+// from ParseSV but it does not allocate.
+func IsValid(s string) bool {
+	s, ok := cutPrefix(s)
+	if !ok {
+		return false
+	}
+
+	_, ok = scanSV(s)
+
+	return ok
+}
+
+// IsValidStrict returns true if the string is a well-formed semantic
+// version ID without the leading 'v'. It gives the same result as checking
+// for an error from ParseStrictSV but it does not allocate.
+func IsValidStrict(s string) bool {
+	_, ok := scanSV(s)
+
+	return ok
+}
+
+// IsValidBytes returns true if the slice of bytes holds a well-formed
+// semantic version ID with the leading 'v'. It does not allocate.
+func IsValidBytes(b []byte) bool {
+	b, ok := cutPrefix(b)
+	if !ok {
+		return false
+	}
+
+	_, ok = scanSV(b)
+
+	return ok
+}
+
+// ParseBytes is like ParseSV but takes a slice of bytes. A well-formed
+// semantic version ID is parsed without using regular expressions; the
+// error for a badly-formed one is as for ParseSV.
+//
+// Parsing a well-formed version is not allocation-free. It makes two
+// allocations: the SV itself and a string copy of the bytes, which is
+// needed because the caller may change the slice afterwards; the version
+// numbers and IDs of the SV refer to this copy. If the version has any
+// pre-release or build IDs there is a third allocation for the slice
+// holding them. Use IsValidBytes if you only need to check the version.
+func ParseBytes(b []byte) (*SV, error) {
+	if core, ok := cutPrefix(b); ok {
+		if l, ok := scanSV(core); ok {
+			return l.mkSV(string(core)), nil
+		}
+	}
+
+	return ParseSV(string(b))
+}
+
+// ParseStrictBytes is like ParseStrictSV but takes a slice of bytes. See
+// ParseBytes for details.
+func ParseStrictBytes(b []byte) (*SV, error) {
+	if l, ok := scanSV(b); ok {
+		return l.mkSV(string(b)), nil
+	}
+
+	return ParseStrictSV(string(b))
+}
@@ -0,0 +1,222 @@
+package semver_test
+
+import (
+	"bufio"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/nickwells/semver.mod/v3/semver"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+// readSemVers returns the first tab-separated field of each non-blank line
+// in the file
+func readSemVers(tb testing.TB, fname string) []string {
+	tb.Helper()
+
+	file, err := os.Open(fname)
+	if err != nil {
+		tb.Fatal("Cannot open the test file: ", fname, " - ", err)
+	}
+	defer file.Close()
+
+	svStrs := []string{}
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if line := scanner.Text(); line != "" {
+			svStrs = append(svStrs, tabFields(line)[0])
+		}
+	}
+
+	return svStrs
+}
+
+func TestIsValid(t *testing.T) {
+	svStrs := append(readSemVers(t, "testdata/goodSemVers"),
+		readSemVers(t, "testdata/badSemVers")...)
+	svStrs = append(svStrs,
+		"", "v", "1.2.3", "v1.2.3-", "v1.2.3+", "v1.2.3-a.", "v1.2.3-a..b",
+		"v1.2.3-+b", "v1.2.3+b.01", "v1.2.3-01", "v1.2.3.4", "v1.2.-3",
+		"v9223372036854775807.0.0", "v9223372036854775808.0.0")
+
+	for _, s := range svStrs {
+		_, err := semver.ParseSV(s)
+		exp := err == nil
+
+		testhelper.DiffBool(t, s, "IsValid", semver.IsValid(s), exp)
+		testhelper.DiffBool(t, s, "IsValidBytes",
+			semver.IsValidBytes([]byte(s)), exp)
+
+		if strict, ok := strings.CutPrefix(s, "v"); ok {
+			testhelper.DiffBool(t, s, "IsValidStrict",
+				semver.IsValidStrict(strict), exp)
+		}
+	}
+}
+
+func TestParseBytes(t *testing.T) {
+	svStrs := append(readSemVers(t, "testdata/goodSemVers"),
+		readSemVers(t, "testdata/badSemVers")...)
+
+	for _, s := range svStrs {
+		expSV, expErr := semver.ParseSV(s)
+
+		sv, err := semver.ParseBytes([]byte(s))
+		if (err == nil) != (expErr == nil) {
+			t.Log(s)
+			t.Errorf("\t: ParseBytes error: %v, ParseSV error: %v",
+				err, expErr)
+
+			continue
+		}
+
+		if err != nil {
+			testhelper.DiffString(t, s, "error", err.Error(), expErr.Error())
+
+			continue
+		}
+
+		testhelper.DiffString(t, s, "SV", sv.String(), expSV.String())
+		testhelper.DiffStringSlice(t, s, "pre-release IDs",
+			sv.PreRelIDs(), expSV.PreRelIDs())
+		testhelper.DiffStringSlice(t, s, "build IDs",
+			sv.BuildIDs(), expSV.BuildIDs())
+
+		strictSV, err := semver.ParseStrictBytes([]byte(s[1:]))
+		if err != nil {
+			t.Log(s)
+			t.Errorf("\t: ParseStrictBytes: unexpected error: %v", err)
+
+			continue
+		}
+
+		testhelper.DiffString(t, s, "strict SV",
+			strictSV.String(), expSV.String())
+	}
+}
+
+func TestParseBytesIDsAreSeparate(t *testing.T) {
+	sv, err := semver.ParseBytes([]byte("v1.2.3-a.b+c"))
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+
+	prIDs := sv.PreRelIDs()
+	_ = append(prIDs, "x")
+
+	testhelper.DiffStringSlice(t, "append to pre-release IDs", "build IDs",
+		sv.BuildIDs(), []string{"c"})
+}
+
+func TestParseAllocs(t *testing.T) {
+	svStrs := readSemVers(t, "testdata/goodSemVers")
+	svBytes := make([][]byte, 0, len(svStrs))
+
+	for _, s := range svStrs {
+		svBytes = append(svBytes, []byte(s))
+	}
+
+	allocs := testing.AllocsPerRun(10, func() {
+		for _, s := range svStrs {
+			_ = semver.IsValid(s)
+		}
+
+		for _, b := range svBytes {
+			_ = semver.IsValidBytes(b)
+		}
+	})
+	testhelper.DiffFloat(t, "IsValid", "allocations", allocs, 0, 0)
+
+	// ParseBytes allocates the SV and a copy of the string and, if there
+	// are any IDs, the slice holding them
+	const (
+		allocsPerParse = 2
+		allocsForIDs   = 1
+	)
+
+	for _, b := range svBytes {
+		sv, err := semver.ParseBytes(b)
+		if err != nil {
+			t.Fatal("unexpected error: ", err)
+		}
+
+		expAllocs := allocsPerParse
+		if sv.HasPreRelIDs() || sv.HasBuildIDs() {
+			expAllocs += allocsForIDs
+		}
+
+		allocs := testing.AllocsPerRun(10, func() {
+			_, _ = semver.ParseBytes(b)
+		})
+		testhelper.DiffFloat(t, string(b), "ParseBytes allocations",
+			allocs, float64(expAllocs), 0)
+	}
+}
+
+func BenchmarkIsValid(b *testing.B) {
+	svStrs := readSemVers(b, "testdata/goodSemVers")
+
+	b.ReportAllocs()
+
+	for b.Loop() {
+		for _, s := range svStrs {
+			if !semver.IsValid(s) {
+				b.Fatal("unexpected invalid version: ", s)
+			}
+		}
+	}
+}
+
+func BenchmarkIsValidBytes(b *testing.B) {
+	svStrs := readSemVers(b, "testdata/goodSemVers")
+	svBytes := make([][]byte, 0, len(svStrs))
+
+	for _, s := range svStrs {
+		svBytes = append(svBytes, []byte(s))
+	}
+
+	b.ReportAllocs()
+
+	for b.Loop() {
+		for _, sb := range svBytes {
+			if !semver.IsValidBytes(sb) {
+				b.Fatal("unexpected invalid version: ", string(sb))
+			}
+		}
+	}
+}
+
+func BenchmarkParseBytes(b *testing.B) {
+	svStrs := readSemVers(b, "testdata/goodSemVers")
+	svBytes := make([][]byte, 0, len(svStrs))
+
+	for _, s := range svStrs {
+		svBytes = append(svBytes, []byte(s))
+	}
+
+	b.ReportAllocs()
+
+	for b.Loop() {
+		for _, sb := range svBytes {
+			if _, err := semver.ParseBytes(sb); err != nil {
+				b.Fatal("unexpected error: ", err)
+			}
+		}
+	}
+}
+
+func BenchmarkParseSV(b *testing.B) {
+	svStrs := readSemVers(b, "testdata/goodSemVers")
+
+	b.ReportAllocs()
+
+	for b.Loop() {
+		for _, s := range svStrs {
+			if _, err := semver.ParseSV(s); err != nil {
+				b.Fatal("unexpected error: ", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package semver
+
+import (
+	"errors"
+	"fmt"
+)
+
+// SVPart identifies a part of a semantic version ID
+type SVPart int
+
+const (
+	// PartPrefix is the leading 'v'
+	PartPrefix SVPart = iota
+	// PartMajor is the major version number
+	PartMajor
+	// PartMinor is the minor version number
+	PartMinor
+	// PartPatch is the patch version number
+	PartPatch
+	// PartPreRel is the pre-release IDs
+	PartPreRel
+	// PartBuild is the build IDs
+	PartBuild
+)
+
+// String returns the name of the part
+func (p SVPart) String() string {
+	switch p {
+	case PartPrefix:
+		return "prefix"
+	case PartMajor:
+		return "major"
+	case PartMinor:
+		return "minor"
+	case PartPatch:
+		return "patch"
+	case PartPreRel:
+		return "pre-release"
+	case PartBuild:
+		return "build"
+	}
+
+	return fmt.Sprintf("SVPart(%d)", int(p))
+}
+
+// These are the kinds of error that can be found when parsing an SV. They
+// can be tested for using errors.Is
+var (
+	// ErrNoPrefix means the leading 'v' is missing
+	ErrNoPrefix = errors.New("the leading 'v' is missing")
+	// ErrMissingPart means that the minor or patch version is missing
+	ErrMissingPart = errors.New("a version number is missing")
+	// ErrEmpty means that a version number or ID is empty
+	ErrEmpty = errors.New("a version number or ID is empty")
+	// ErrNotNumeric means that a version number is not a number
+	ErrNotNumeric = errors.New("a version number is not a number")
+	// ErrLeadingZero means that a number has a leading zero
+	ErrLeadingZero = errors.New("a number has a leading zero")
+	// ErrBadChar means that an ID has a character which is not allowed
+	ErrBadChar = errors.New("an ID has a bad character")
+)
+
+// kindErr is an error which reports the kind of error it is when tested
+// with errors.Is. The error message is unaffected by the kind.
+type kindErr struct {
+	kind error
+	msg  string
+}
+
+// Error returns the error message
+func (e kindErr) Error() string { return e.msg }
+
+// Unwrap returns the kind of the error
+func (e kindErr) Unwrap() error { return e.kind }
+
+// ParseError records a problem found when parsing an SV. It reports which
+// part of the SV is bad and where in the input that part starts. The Kind
+// of the error can be tested for using errors.Is.
+type ParseError struct {
+	// Input is the string being parsed
+	Input string
+	// Offset is the byte offset in the Input of the start of the offending
+	// item. This is the start of the bad version number or ID or, if a
+	// version number is missing, the end of the version numbers (before any
+	// pre-release or build IDs); so for "v1.2-rc" it is 4
+	Offset int
+	// Part is the part of the SV which is bad
+	Part SVPart
+	// Kind is the kind of error, one of the Err... values
+	Kind error
+
+	msg string
+}
+
+// newParseError returns a ParseError with the kind taken from the error
+func newParseError(input string, offset int, part SVPart, err error,
+) *ParseError {
+	pe := &ParseError{
+		Input:  input,
+		Offset: offset,
+		Part:   part,
+		msg:    err.Error(),
+	}
+
+	var ke kindErr
+	if errors.As(err, &ke) {
+		pe.Kind = ke.kind
+	}
+
+	return pe
+}
+
+// Error returns the error message
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("bad %s - %s", Name, e.msg)
+}
+
+// Unwrap returns the kind of the error
+func (e *ParseError) Unwrap() error { return e.Kind }
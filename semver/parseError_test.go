@@ -0,0 +1,130 @@
+package semver_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/nickwells/semver.mod/v3/semver"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestParseError(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		parse     func(string) (*semver.SV, error)
+		input     string
+		expOffset int
+		expPart   semver.SVPart
+		expKind   error
+		expMsg    string
+	}{
+		{
+			ID:        testhelper.MkID("ParseStrictSV - offset has no prefix"),
+			parse:     semver.ParseStrictSV,
+			input:     "1.02.3",
+			expOffset: 2,
+			expPart:   semver.PartMinor,
+			expKind:   semver.ErrLeadingZero,
+			expMsg: "bad " + semver.Name +
+				` - the minor version: "02" has a leading 0`,
+		},
+		{
+			ID:        testhelper.MkID("ParseSV - offset includes the prefix"),
+			parse:     semver.ParseSV,
+			input:     "v1.02.3",
+			expOffset: 3,
+			expPart:   semver.PartMinor,
+			expKind:   semver.ErrLeadingZero,
+			expMsg: "bad " + semver.Name +
+				` - the minor version: "02" has a leading 0`,
+		},
+		{
+			ID:        testhelper.MkID("ParseSV - missing patch version"),
+			parse:     semver.ParseSV,
+			input:     "v1.2-rc",
+			expOffset: 4,
+			expPart:   semver.PartPatch,
+			expKind:   semver.ErrMissingPart,
+			expMsg: "bad " + semver.Name +
+				" - it cannot be split into major/minor/patch parts",
+		},
+		{
+			ID:        testhelper.MkID("ParseSV - bad pre-release ID"),
+			parse:     semver.ParseSV,
+			input:     "v1.2.3-rc.0_1",
+			expOffset: 10,
+			expPart:   semver.PartPreRel,
+			expKind:   semver.ErrBadChar,
+			expMsg: "bad " + semver.Name +
+				" - the Pre-Rel ID: '0_1' must be " + semver.GoodIDDesc,
+		},
+	}
+
+	for _, tc := range testCases {
+		_, err := tc.parse(tc.input)
+
+		var pe *semver.ParseError
+		if !errors.As(err, &pe) {
+			t.Log(tc.IDStr())
+			t.Errorf("\t: expected a *ParseError, got: %T", err)
+
+			continue
+		}
+
+		testhelper.DiffString(t, tc.IDStr(), "input", pe.Input, tc.input)
+		testhelper.DiffInt(t, tc.IDStr(), "offset", pe.Offset, tc.expOffset)
+		testhelper.DiffString(t, tc.IDStr(), "part",
+			pe.Part.String(), tc.expPart.String())
+		testhelper.DiffBool(t, tc.IDStr(), "errors.Is",
+			errors.Is(err, tc.expKind), true)
+		testhelper.DiffString(t, tc.IDStr(), "message", err.Error(), tc.expMsg)
+	}
+}
+
+func TestParseErrorWrapped(t *testing.T) {
+	_, err := semver.ParseConstraint(">=1.02.3")
+
+	var pe *semver.ParseError
+	if !errors.As(err, &pe) {
+		t.Fatalf("expected a *ParseError in the error chain, got: %v", err)
+	}
+
+	testhelper.DiffBool(t, "constraint", "errors.Is",
+		errors.Is(err, semver.ErrLeadingZero), true)
+	testhelper.DiffString(t, "constraint", "part",
+		pe.Part.String(), semver.PartMinor.String())
+}
+
+func TestCheckIDErrKind(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		check   func(string) error
+		id      string
+		expKind error
+	}{
+		{
+			ID:      testhelper.MkID("pre-release - leading zero"),
+			check:   semver.CheckPreRelID,
+			id:      "01",
+			expKind: semver.ErrLeadingZero,
+		},
+		{
+			ID:      testhelper.MkID("pre-release - empty"),
+			check:   semver.CheckPreRelID,
+			id:      "",
+			expKind: semver.ErrEmpty,
+		},
+		{
+			ID:      testhelper.MkID("build - bad char"),
+			check:   semver.CheckBuildID,
+			id:      "a.b",
+			expKind: semver.ErrBadChar,
+		},
+	}
+
+	for _, tc := range testCases {
+		err := tc.check(tc.id)
+		testhelper.DiffBool(t, tc.IDStr(), "errors.Is",
+			errors.Is(err, tc.expKind), true)
+	}
+}
@@ -0,0 +1,138 @@
+package semver
+
+import "fmt"
+
+// newPreRelIDs returns the pre-release IDs starting a new pre-release: the
+// channel (if it is not empty) followed by "0". It returns an error if the
+// channel is not a valid pre-release ID.
+func newPreRelIDs(channel string) ([]string, error) {
+	if channel == "" {
+		return []string{"0"}, nil
+	}
+
+	if err := CheckPreRelID(channel); err != nil {
+		return nil, fmt.Errorf("bad pre-release channel: %q - %w", channel, err)
+	}
+
+	return []string{channel, "0"}, nil
+}
+
+// IncrPreRelease moves the SV on to the next pre-release. The channel is
+// the name of the pre-release, such as "alpha" or "rc", and may be empty.
+// This follows the semantics of "semver inc prerelease" in npm:
+//
+//   - if the SV is a release version, the patch number is incremented and
+//     the pre-release IDs are set to the channel followed by "0"; so
+//     v1.2.3 becomes v1.2.4-rc.0 (or v1.2.4-0 if the channel is empty)
+//   - if the channel is given and is not the first of the pre-release IDs,
+//     the pre-release IDs are set to the channel followed by "0"; so
+//     v1.2.4-alpha.3 becomes v1.2.4-rc.0
+//   - if the channel is given and is the first of the pre-release IDs but
+//     the second ID is missing or is not numeric, the pre-release IDs are
+//     set to the channel followed by "0"; so v1.2.4-rc.foo.1 becomes
+//     v1.2.4-rc.0 (note that, as with npm, this is a lower version)
+//   - otherwise the rightmost numeric pre-release ID is incremented or, if
+//     there are no numeric IDs, a "0" is added; so v1.2.4-rc.1 becomes
+//     v1.2.4-rc.2 and v1.2.4-rc becomes v1.2.4-rc.0
+//
+// The build IDs are not changed. It returns an error, and the SV is not
+// changed, if the channel is not a valid pre-release ID.
+func (sv *SV) IncrPreRelease(channel string) error {
+	startIDs, err := newPreRelIDs(channel)
+	if err != nil {
+		return err
+	}
+
+	if !sv.HasPreRelIDs() {
+		sv.patch = sv.patch.incr()
+		sv.preRelIDs = startIDs
+
+		return nil
+	}
+
+	if channel != "" &&
+		(sv.preRelIDs[0] != channel ||
+			len(sv.preRelIDs) < 2 ||
+			!goodNumericRE.MatchString(sv.preRelIDs[1])) {
+		sv.preRelIDs = startIDs
+
+		return nil
+	}
+
+	ids := make([]string, len(sv.preRelIDs), len(sv.preRelIDs)+1)
+	copy(ids, sv.preRelIDs)
+
+	for i := len(ids) - 1; i >= 0; i-- {
+		if goodNumericRE.MatchString(ids[i]) {
+			ids[i] = string(vNum(ids[i]).incr())
+			sv.preRelIDs = ids
+
+			return nil
+		}
+	}
+
+	sv.preRelIDs = append(ids, "0")
+
+	return nil
+}
+
+// PreMajor increments the major version number, sets the minor and patch
+// numbers to 0 and starts a new pre-release with the pre-release IDs set to
+// the channel (if it is not empty) followed by "0". So v1.2.3 becomes
+// v2.0.0-alpha.0 for the channel "alpha". The build IDs are not changed. It
+// returns an error, and the SV is not changed, if the channel is not a
+// valid pre-release ID.
+func (sv *SV) PreMajor(channel string) error {
+	ids, err := newPreRelIDs(channel)
+	if err != nil {
+		return err
+	}
+
+	sv.IncrMajor()
+	sv.preRelIDs = ids
+
+	return nil
+}
+
+// PreMinor increments the minor version number, sets the patch number to 0
+// and starts a new pre-release as for PreMajor. So v1.3.2 becomes
+// v1.4.0-alpha.0 for the channel "alpha".
+func (sv *SV) PreMinor(channel string) error {
+	ids, err := newPreRelIDs(channel)
+	if err != nil {
+		return err
+	}
+
+	sv.IncrMinor()
+	sv.preRelIDs = ids
+
+	return nil
+}
+
+// PrePatch increments the patch number and starts a new pre-release as for
+// PreMajor. So v1.3.2 becomes v1.3.3-alpha.0 for the channel "alpha".
+func (sv *SV) PrePatch(channel string) error {
+	ids, err := newPreRelIDs(channel)
+	if err != nil {
+		return err
+	}
+
+	sv.IncrPatch()
+	sv.preRelIDs = ids
+
+	return nil
+}
+
+// Release turns a pre-release version into the corresponding release by
+// removing the pre-release IDs. So v1.4.0-rc.3 becomes v1.4.0. The build
+// IDs are not changed. It returns an error if the SV is not a pre-release
+// version.
+func (sv *SV) Release() error {
+	if !sv.HasPreRelIDs() {
+		return fmt.Errorf("%s is not a pre-release version", sv)
+	}
+
+	sv.ClearPreRelIDs()
+
+	return nil
+}
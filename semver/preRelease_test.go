@@ -0,0 +1,200 @@
+package semver_test
+
+import (
+	"testing"
+
+	"github.com/nickwells/semver.mod/v3/semver"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestPreReleaseIncr(t *testing.T) {
+	incrPreRel := func(sv *semver.SV, ch string) error {
+		return sv.IncrPreRelease(ch)
+	}
+	preMajor := func(sv *semver.SV, ch string) error { return sv.PreMajor(ch) }
+	preMinor := func(sv *semver.SV, ch string) error { return sv.PreMinor(ch) }
+	prePatch := func(sv *semver.SV, ch string) error { return sv.PrePatch(ch) }
+	release := func(sv *semver.SV, _ string) error { return sv.Release() }
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		svStr    string
+		incr     func(*semver.SV, string) error
+		channel  string
+		expSVStr string
+		// expLower is set if the new version is expected to be lower
+		expLower bool
+	}{
+		{
+			ID:       testhelper.MkID("IncrPreRelease - next rc"),
+			svStr:    "v1.4.0-rc.1",
+			incr:     incrPreRel,
+			expSVStr: "v1.4.0-rc.2",
+		},
+		{
+			ID:       testhelper.MkID("IncrPreRelease - same channel"),
+			svStr:    "v1.4.0-rc.9+b",
+			incr:     incrPreRel,
+			channel:  "rc",
+			expSVStr: "v1.4.0-rc.10+b",
+		},
+		{
+			ID:       testhelper.MkID("IncrPreRelease - same channel, not numeric"),
+			svStr:    "v1.2.4-rc.foo.1",
+			incr:     incrPreRel,
+			channel:  "rc",
+			expSVStr: "v1.2.4-rc.0",
+			expLower: true,
+		},
+		{
+			ID:       testhelper.MkID("IncrPreRelease - same channel, alone"),
+			svStr:    "v1.2.4-rc",
+			incr:     incrPreRel,
+			channel:  "rc",
+			expSVStr: "v1.2.4-rc.0",
+		},
+		{
+			ID:       testhelper.MkID("IncrPreRelease - same channel, rightmost"),
+			svStr:    "v1.2.4-rc.1.5",
+			incr:     incrPreRel,
+			channel:  "rc",
+			expSVStr: "v1.2.4-rc.1.6",
+		},
+		{
+			ID:       testhelper.MkID("IncrPreRelease - no channel, not numeric"),
+			svStr:    "v1.2.4-rc.foo.1",
+			incr:     incrPreRel,
+			expSVStr: "v1.2.4-rc.foo.2",
+		},
+		{
+			ID:       testhelper.MkID("IncrPreRelease - rightmost numeric ID"),
+			svStr:    "v1.4.0-1.rc.2.x",
+			incr:     incrPreRel,
+			expSVStr: "v1.4.0-1.rc.3.x",
+		},
+		{
+			ID:       testhelper.MkID("IncrPreRelease - no numeric ID"),
+			svStr:    "v1.4.0-rc",
+			incr:     incrPreRel,
+			expSVStr: "v1.4.0-rc.0",
+		},
+		{
+			ID:       testhelper.MkID("IncrPreRelease - big numeric ID"),
+			svStr:    "v1.4.0-rc.99999999999999999999",
+			incr:     incrPreRel,
+			expSVStr: "v1.4.0-rc.100000000000000000000",
+		},
+		{
+			ID:       testhelper.MkID("IncrPreRelease - new channel"),
+			svStr:    "v1.4.0-alpha.3",
+			incr:     incrPreRel,
+			channel:  "beta",
+			expSVStr: "v1.4.0-beta.0",
+		},
+		{
+			ID:       testhelper.MkID("IncrPreRelease - from a release"),
+			svStr:    "v1.3.2",
+			incr:     incrPreRel,
+			expSVStr: "v1.3.3-0",
+		},
+		{
+			ID:       testhelper.MkID("IncrPreRelease - release, channel"),
+			svStr:    "v1.3.2+b",
+			incr:     incrPreRel,
+			channel:  "rc",
+			expSVStr: "v1.3.3-rc.0+b",
+		},
+		{
+			ID:      testhelper.MkID("IncrPreRelease - bad channel"),
+			svStr:   "v1.3.2-rc.1",
+			incr:    incrPreRel,
+			channel: "r.c",
+			ExpErr: testhelper.MkExpErr(`bad pre-release channel: "r.c"`,
+				"the Pre-Rel ID: 'r.c' must be "+semver.GoodIDDesc),
+			expSVStr: "v1.3.2-rc.1",
+		},
+		{
+			ID:       testhelper.MkID("PreMajor"),
+			svStr:    "v1.3.2-rc.1+b",
+			incr:     preMajor,
+			channel:  "alpha",
+			expSVStr: "v2.0.0-alpha.0+b",
+		},
+		{
+			ID:       testhelper.MkID("PreMajor - no channel"),
+			svStr:    "v1.3.2",
+			incr:     preMajor,
+			expSVStr: "v2.0.0-0",
+		},
+		{
+			ID:       testhelper.MkID("PreMinor"),
+			svStr:    "v1.3.2",
+			incr:     preMinor,
+			channel:  "alpha",
+			expSVStr: "v1.4.0-alpha.0",
+		},
+		{
+			ID:       testhelper.MkID("PrePatch"),
+			svStr:    "v1.3.2",
+			incr:     prePatch,
+			channel:  "alpha",
+			expSVStr: "v1.3.3-alpha.0",
+		},
+		{
+			ID:       testhelper.MkID("PrePatch - bad channel"),
+			svStr:    "v1.3.2",
+			incr:     prePatch,
+			channel:  "01",
+			ExpErr:   testhelper.MkExpErr(`bad pre-release channel: "01"`),
+			expSVStr: "v1.3.2",
+		},
+		{
+			ID:       testhelper.MkID("Release"),
+			svStr:    "v1.4.0-rc.3+b",
+			incr:     release,
+			expSVStr: "v1.4.0+b",
+		},
+		{
+			ID:    testhelper.MkID("Release - not a pre-release"),
+			svStr: "v1.4.0",
+			incr:  release,
+			ExpErr: testhelper.MkExpErr(
+				"v1.4.0 is not a pre-release version"),
+			expSVStr: "v1.4.0",
+		},
+	}
+
+	for _, tc := range testCases {
+		sv, err := semver.ParseSV(tc.svStr)
+		if err != nil {
+			t.Fatal("Couldn't parse the semver: ", err)
+		}
+
+		orig := &semver.SV{}
+		sv.CopyInto(orig)
+
+		err = tc.incr(sv, tc.channel)
+		testhelper.CheckExpErr(t, err, tc)
+		testhelper.DiffString(t, tc.IDStr(), "SV", sv.String(), tc.expSVStr)
+
+		if err == nil && !tc.expLower && !semver.Less(orig, sv) &&
+			!semver.EqualPrecedence(orig, sv) {
+			t.Log(tc.IDStr())
+			t.Errorf("\t: the new version (%s) should not be less than %s",
+				sv, orig)
+		}
+	}
+}
+
+func TestIncrPreReleaseNoAlias(t *testing.T) {
+	sv := semver.NewSVOrPanic(1, 0, 0, []string{"rc", "1"}, nil)
+	ids := sv.PreRelIDs()
+
+	if err := sv.IncrPreRelease(""); err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+
+	testhelper.DiffStringSlice(t, "IncrPreRelease", "original IDs",
+		ids, []string{"rc", "1"})
+}
@@ -3,8 +3,9 @@ package semver
 import (
 	"errors"
 	"fmt"
+	"math/big"
 	"regexp"
-	"strconv"
+	"slices"
 	"strings"
 
 	"github.com/nickwells/check.mod/v2/check"
@@ -49,9 +50,9 @@ func init() {
 
 // SV holds the parts of a semantic version number
 type SV struct {
-	major     int
-	minor     int
-	patch     int
+	major     vNum
+	minor     vNum
+	patch     vNum
 	preRelIDs []string
 	buildIDs  []string
 
@@ -62,26 +63,44 @@ type SV struct {
 // for a build ID, an error otherwise
 func CheckBuildID(id string) error {
 	if !idRE.MatchString(id) {
-		return errors.New("the Build ID: '" + id + "' must be " + GoodIDDesc)
+		return kindErr{
+			kind: idErrKind(id),
+			msg:  "the Build ID: '" + id + "' must be " + GoodIDDesc,
+		}
 	}
 
 	return nil
 }
 
+// idErrKind returns the kind of error for an ID which is not well-formed
+func idErrKind(id string) error {
+	if id == "" {
+		return ErrEmpty
+	}
+
+	return ErrBadChar
+}
+
 // CheckPreRelID returns nil if the id is a well-formed semver ID - suitable
 // for a pre-release ID, an error otherwise
 func CheckPreRelID(id string) error {
 	if numericOnlyRE.MatchString(id) {
 		if !goodNumericRE.MatchString(id) {
-			return errors.New("the Pre-Rel ID: '" + id +
-				"' must have no leading zero if it's all numeric")
+			return kindErr{
+				kind: ErrLeadingZero,
+				msg: "the Pre-Rel ID: '" + id +
+					"' must have no leading zero if it's all numeric",
+			}
 		}
 
 		return nil
 	}
 
 	if !idRE.MatchString(id) {
-		return errors.New("the Pre-Rel ID: '" + id + "' must be " + GoodIDDesc)
+		return kindErr{
+			kind: idErrKind(id),
+			msg:  "the Pre-Rel ID: '" + id + "' must be " + GoodIDDesc,
+		}
 	}
 
 	return nil
@@ -117,31 +136,32 @@ func (sv SV) Check() error {
 		return err
 	}
 
-	if err := CheckAllBuildIDs(sv.buildIDs); err != nil {
-		return err
-	}
+	return CheckAllBuildIDs(sv.buildIDs)
+}
 
-	if sv.major < 0 {
+// checkIntVNums returns an error if any of the version numbers is negative
+func checkIntVNums(major, minor, patch int) error {
+	if major < 0 {
 		return fmt.Errorf("bad major version: %d - it must be %s",
-			sv.major, GoodVsnNumDesc)
+			major, GoodVsnNumDesc)
 	}
 
-	if sv.minor < 0 {
+	if minor < 0 {
 		return fmt.Errorf("bad minor version: %d - it must be %s",
-			sv.minor, GoodVsnNumDesc)
+			minor, GoodVsnNumDesc)
 	}
 
-	if sv.patch < 0 {
+	if patch < 0 {
 		return fmt.Errorf("bad patch version: %d - it must be %s",
-			sv.patch, GoodVsnNumDesc)
+			patch, GoodVsnNumDesc)
 	}
 
 	return nil
 }
 
-// NewSV returns a pointer to a properly constructed SV or an error if the
+// newSV returns a pointer to a properly constructed SV or an error if the
 // IDs are not well-formed
-func NewSV(major, minor, patch int, prIDs, buildIDs []string) (*SV, error) {
+func newSV(major, minor, patch vNum, prIDs, buildIDs []string) (*SV, error) {
 	sv := &SV{
 		major:      major,
 		minor:      minor,
@@ -158,6 +178,19 @@ func NewSV(major, minor, patch int, prIDs, buildIDs []string) (*SV, error) {
 	return sv, nil
 }
 
+// NewSV returns a pointer to a properly constructed SV or an error if the
+// IDs are not well-formed or any of the version numbers is negative. The
+// SV holds copies of the slices of IDs so later changes to them will not
+// affect it.
+func NewSV(major, minor, patch int, prIDs, buildIDs []string) (*SV, error) {
+	if err := checkIntVNums(major, minor, patch); err != nil {
+		return nil, err
+	}
+
+	return newSV(intVNum(major), intVNum(minor), intVNum(patch),
+		slices.Clone(prIDs), slices.Clone(buildIDs))
+}
+
 // NewSVOrPanic returns a pointer to a properly constructed SV. If there were
 // any errors it will panic.
 func NewSVOrPanic(major, minor, patch int, prIDs, buildIDs []string) *SV {
@@ -192,16 +225,8 @@ func NewSVWithIDRules(major, minor, patch int,
 	prIDs, buildIDs []string,
 	prIDRules, bIDRules []check.ValCk[[]string],
 ) (*SV, error) {
-	sv := &SV{
-		major:      major,
-		minor:      minor,
-		patch:      patch,
-		preRelIDs:  prIDs,
-		buildIDs:   buildIDs,
-		hasBeenSet: true,
-	}
-
-	if err := sv.Check(); err != nil {
+	sv, err := NewSV(major, minor, patch, prIDs, buildIDs)
+	if err != nil {
 		return nil, err
 	}
 
@@ -235,23 +260,56 @@ func NewSVWithIDRulesOrPanic(major, minor, patch int,
 // ParseSV will parse the semver string into an SV object. It will strip off
 // the leading 'v' which must be present. It will return a pointer to a
 // properly constructed SV and a nil error if the semver is well-formed or a
-// nil pointer and an error otherwise
+// nil pointer and an error otherwise. The error will be a *ParseError.
 func ParseSV(semver string) (*SV, error) {
 	s, ok := strings.CutPrefix(semver, semverPrefix)
 	if !ok {
-		return nil,
-			fmt.Errorf("bad %s - it does not start with a 'v'", Name)
+		return nil, newParseError(semver, 0, PartPrefix,
+			kindErr{kind: ErrNoPrefix, msg: "it does not start with a 'v'"})
+	}
+
+	sv, err := ParseStrictSV(s)
+	if err != nil {
+		var pe *ParseError
+		if errors.As(err, &pe) {
+			pe.Input = semver
+			pe.Offset += len(semverPrefix)
+		}
+
+		return nil, err
+	}
+
+	return sv, nil
+}
+
+// checkIDs checks each of the IDs using the check func and returns a
+// ParseError for the first bad one. The IDs start at the offset in the
+// input.
+func checkIDs(input string, offset int, part SVPart,
+	ids []string, chk func(string) error,
+) error {
+	for _, id := range ids {
+		if err := chk(id); err != nil {
+			return newParseError(input, offset, part, err)
+		}
+
+		offset += len(id) + len(semverPartSeparator)
 	}
 
-	return ParseStrictSV(s)
+	return nil
 }
 
 // ParseStrictSV will parse the semver string into an SV object. It is
 // expected to have no prefix. It will return a pointer to a properly
 // constructed SV and a nil error if the semver is well-formed or a nil
-// pointer and an error otherwise
+// pointer and an error otherwise. The error will be a *ParseError.
 func ParseStrictSV(semver string) (*SV, error) {
+	if l, ok := scanSV(semver); ok {
+		return l.mkSV(semver), nil
+	}
+
 	sv := SV{}
+	input := semver
 
 	var err error
 
@@ -262,40 +320,47 @@ func ParseStrictSV(semver string) (*SV, error) {
 	semver, buildIDs, ok = strings.Cut(semver, semverBuildIDsSeparator)
 	if ok {
 		sv.buildIDs = strings.Split(buildIDs, semverPartSeparator)
-		if err = CheckAllBuildIDs(sv.buildIDs); err != nil {
-			return nil, fmt.Errorf("bad %s - %s", Name, err)
+
+		err = checkIDs(input, len(semver)+len(semverBuildIDsSeparator),
+			PartBuild, sv.buildIDs, CheckBuildID)
+		if err != nil {
+			return nil, err
 		}
 	}
 
 	semver, preRelIDs, ok = strings.Cut(semver, semverPreRelIDsSeparator)
 	if ok {
 		sv.preRelIDs = strings.Split(preRelIDs, semverPartSeparator)
-		if err = CheckAllPreRelIDs(sv.preRelIDs); err != nil {
-			return nil, fmt.Errorf("bad %s - %s", Name, err)
+
+		err = checkIDs(input, len(semver)+len(semverPreRelIDsSeparator),
+			PartPreRel, sv.preRelIDs, CheckPreRelID)
+		if err != nil {
+			return nil, err
 		}
 	}
 
 	parts := strings.SplitN(semver, semverPartSeparator, semverVsnPartCount)
 	if len(parts) != semverVsnPartCount {
-		return nil,
-			fmt.Errorf("bad %s"+
-				" - it cannot be split into major/minor/patch parts",
-				Name)
+		return nil, newParseError(input, len(semver),
+			PartMajor+SVPart(len(parts)),
+			kindErr{
+				kind: ErrMissingPart,
+				msg:  "it cannot be split into major/minor/patch parts",
+			})
 	}
 
-	sv.major, err = strToVNum(parts[semverMajorVsnIdx], "major")
-	if err != nil {
-		return nil, fmt.Errorf("bad %s - %s", Name, err)
-	}
+	vNums := [semverVsnPartCount]*vNum{&sv.major, &sv.minor, &sv.patch}
+	offset := 0
 
-	sv.minor, err = strToVNum(parts[semverMinorVsnIdx], "minor")
-	if err != nil {
-		return nil, fmt.Errorf("bad %s - %s", Name, err)
-	}
+	for i, part := range parts {
+		svPart := PartMajor + SVPart(i)
 
-	sv.patch, err = strToVNum(parts[semverPatchVsnIdx], "patch")
-	if err != nil {
-		return nil, fmt.Errorf("bad %s - %s", Name, err)
+		*vNums[i], err = strToVNum(part, svPart.String())
+		if err != nil {
+			return nil, newParseError(input, offset, svPart, err)
+		}
+
+		offset += len(part) + len(semverPartSeparator)
 	}
 
 	sv.hasBeenSet = true
@@ -305,22 +370,41 @@ func ParseStrictSV(semver string) (*SV, error) {
 
 // strToVNum converts a string into a version number and reports any errors
 // it finds
-func strToVNum(s, name string) (int, error) {
+func strToVNum(s, name string) (vNum, error) {
 	if len(s) > 1 && s[0] == '0' {
-		return 0, fmt.Errorf("the %s version: %q has a leading 0", name, s)
+		return "", kindErr{
+			kind: ErrLeadingZero,
+			msg:  fmt.Sprintf("the %s version: %q has a leading 0", name, s),
+		}
 	}
 
-	i, err := strconv.Atoi(s)
-	if err != nil {
-		return 0, fmt.Errorf("the %s version: %q is not an integer", name, s)
+	if isAllDigits(s) {
+		return vNum(s), nil
 	}
 
-	if i < 0 {
-		return 0, fmt.Errorf("the %s version: %q must be %s",
-			name, s, GoodVsnNumDesc)
+	if neg, ok := strings.CutPrefix(s, "-"); ok && isAllDigits(neg) {
+		return "", kindErr{
+			kind: ErrNotNumeric,
+			msg: fmt.Sprintf("the %s version: %q must be %s",
+				name, s, GoodVsnNumDesc),
+		}
 	}
 
-	return i, nil
+	kind := ErrNotNumeric
+	if s == "" {
+		kind = ErrEmpty
+	}
+
+	return "", kindErr{
+		kind: kind,
+		msg:  fmt.Sprintf("the %s version: %q is not an integer", name, s),
+	}
+}
+
+// isAllDigits returns true if the string is not empty and holds only
+// decimal digits
+func isAllDigits(s string) bool {
+	return s != "" && scanWhile(s, 0, isDigit) == len(s)
 }
 
 // CopyInto copies from sv into target - it creates new slices and fills them
@@ -339,24 +423,40 @@ func (sv SV) CopyInto(target *SV) {
 	target.hasBeenSet = sv.hasBeenSet
 }
 
-// Major returns the major version number part of the SemVer
-func (sv SV) Major() int { return sv.major }
+// Major returns the major version number part of the SemVer. It panics if
+// the number is too big to fit in an int; use MajorBig for such versions
+func (sv SV) Major() int { return sv.major.mustInt("major", "MajorBig") }
+
+// Minor returns the minor version number part of the SemVer. It panics if
+// the number is too big to fit in an int; use MinorBig for such versions
+func (sv SV) Minor() int { return sv.minor.mustInt("minor", "MinorBig") }
+
+// Patch returns the patch version number part of the SemVer. It panics if
+// the number is too big to fit in an int; use PatchBig for such versions
+func (sv SV) Patch() int { return sv.patch.mustInt("patch", "PatchBig") }
+
+// MajorBig returns the major version number part of the SemVer as a new
+// big.Int
+func (sv SV) MajorBig() *big.Int { return sv.major.toBig() }
 
-// Minor returns the minor version number part of the SemVer
-func (sv SV) Minor() int { return sv.minor }
+// MinorBig returns the minor version number part of the SemVer as a new
+// big.Int
+func (sv SV) MinorBig() *big.Int { return sv.minor.toBig() }
 
-// Patch returns the patch version number part of the SemVer
-func (sv SV) Patch() int { return sv.patch }
+// PatchBig returns the patch version number part of the SemVer as a new
+// big.Int
+func (sv SV) PatchBig() *big.Int { return sv.patch.toBig() }
 
-// PreRelIDs returns the preRelIDs version number part of the SemVer
-func (sv SV) PreRelIDs() []string { return sv.preRelIDs }
+// PreRelIDs returns a copy of the preRelIDs version number part of the
+// SemVer
+func (sv SV) PreRelIDs() []string { return slices.Clone(sv.preRelIDs) }
 
 // HasPreRelIDs returns true if the preRelIDs version number part of the
 // SemVer is non-empty
 func (sv SV) HasPreRelIDs() bool { return len(sv.preRelIDs) > 0 }
 
-// BuildIDs returns the buildIDs version number part of the SemVer
-func (sv SV) BuildIDs() []string { return sv.buildIDs }
+// BuildIDs returns a copy of the buildIDs version number part of the SemVer
+func (sv SV) BuildIDs() []string { return slices.Clone(sv.buildIDs) }
 
 // HasBuildIDs returns true if the buildIDs version number part of the
 // SemVer is non-empty
@@ -386,9 +486,9 @@ func (sv SV) String() string {
 	}
 
 	return semverPrefix +
-		fmt.Sprintf("%d", sv.major) + semverPartSeparator +
-		fmt.Sprintf("%d", sv.minor) + semverPartSeparator +
-		fmt.Sprintf("%d", sv.patch) +
+		sv.major.String() + semverPartSeparator +
+		sv.minor.String() + semverPartSeparator +
+		sv.patch.String() +
 		prIDs + buildIDs
 }
 
@@ -396,24 +496,24 @@ func (sv SV) String() string {
 // numbers to 0. It also clears the pre-release IDs (if any) but not the build
 // IDs
 func (sv *SV) IncrMajor() {
-	sv.major++
-	sv.minor = 0
-	sv.patch = 0
+	sv.major = sv.major.incr()
+	sv.minor = ""
+	sv.patch = ""
 	sv.ClearPreRelIDs()
 }
 
 // IncrMinor increments the minor version number and sets the patch number to
 // 0. It also clears the pre-release IDs (if any) but not the build IDs
 func (sv *SV) IncrMinor() {
-	sv.minor++
-	sv.patch = 0
+	sv.minor = sv.minor.incr()
+	sv.patch = ""
 	sv.ClearPreRelIDs()
 }
 
 // IncrPatch increments the patch number. It also clears the pre-release IDs
 // (if any) but not the build IDs
 func (sv *SV) IncrPatch() {
-	sv.patch++
+	sv.patch = sv.patch.incr()
 	sv.ClearPreRelIDs()
 }
 
@@ -422,14 +522,14 @@ func (sv *SV) ClearPreRelIDs() {
 	sv.preRelIDs = []string{}
 }
 
-// SetPreRelIDs sets the PreRelIDs
+// SetPreRelIDs sets the PreRelIDs to a copy of the IDs
 func (sv *SV) SetPreRelIDs(ids []string) error {
 	err := CheckAllPreRelIDs(ids)
 	if err != nil {
 		return err
 	}
 
-	sv.preRelIDs = ids
+	sv.preRelIDs = slices.Clone(ids)
 
 	return nil
 }
@@ -439,14 +539,14 @@ func (sv *SV) ClearBuildIDs() {
 	sv.buildIDs = []string{}
 }
 
-// SetBuildIDs sets the BuildIDs
+// SetBuildIDs sets the BuildIDs to a copy of the IDs
 func (sv *SV) SetBuildIDs(ids []string) error {
 	err := CheckAllBuildIDs(ids)
 	if err != nil {
 		return err
 	}
 
-	sv.buildIDs = ids
+	sv.buildIDs = slices.Clone(ids)
 
 	return nil
 }
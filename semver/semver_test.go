@@ -2,7 +2,11 @@ package semver_test
 
 import (
 	"bufio"
+	"cmp"
+	"errors"
+	"fmt"
 	"os"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -304,6 +308,15 @@ func TestIncr(t *testing.T) {
 func TestAllBadStrings(t *testing.T) {
 	const fname = "testdata/badSemVers"
 
+	kinds := map[string]error{
+		"ErrNoPrefix":    semver.ErrNoPrefix,
+		"ErrMissingPart": semver.ErrMissingPart,
+		"ErrEmpty":       semver.ErrEmpty,
+		"ErrNotNumeric":  semver.ErrNotNumeric,
+		"ErrLeadingZero": semver.ErrLeadingZero,
+		"ErrBadChar":     semver.ErrBadChar,
+	}
+
 	file, err := os.Open(fname)
 	if err != nil {
 		t.Fatal("Cannot open the test file: ", fname, " - ", err)
@@ -313,17 +326,61 @@ func TestAllBadStrings(t *testing.T) {
 
 	scanner := bufio.NewScanner(file)
 	for scanner.Scan() {
-		parts := strings.SplitN(scanner.Text(), "\t", 2)
-		if len(parts) != 2 {
+		lineNum++
+
+		parts := tabFields(scanner.Text())
+		if len(parts) == 0 {
 			continue
 		}
 
-		svStr, expectedErr := parts[0], strings.TrimSpace(parts[1])
+		if len(parts) != 5 {
+			t.Fatalf("%s:%d : bad line - expected 5 fields, got %d",
+				fname, lineNum, len(parts))
+		}
+
+		svStr, expPart, expKindName, expOffsetStr, expectedErr :=
+			parts[0], parts[1], parts[2], parts[3], parts[4]
+		id := fmt.Sprintf("%s:%d : %s", fname, lineNum, svStr)
 
 		_, err := semver.ParseSV(svStr)
 		if err == nil {
-			t.Logf("parsing: %s:%d : %s", fname, lineNum, svStr)
+			t.Log(id)
 			t.Errorf("\t: no error was reported, expected: %s", expectedErr)
+
+			continue
+		}
+
+		var pe *semver.ParseError
+		if !errors.As(err, &pe) {
+			t.Log(id)
+			t.Errorf("\t: the error is not a *ParseError: %T", err)
+
+			continue
+		}
+
+		expOffset, convErr := strconv.Atoi(expOffsetStr)
+		if convErr != nil {
+			t.Fatalf("%s : bad offset: %s", id, convErr)
+		}
+
+		expKind, ok := kinds[expKindName]
+		if !ok {
+			t.Fatalf("%s : unknown error kind: %q", id, expKindName)
+		}
+
+		testhelper.DiffString(t, id, "input", pe.Input, svStr)
+		testhelper.DiffInt(t, id, "offset", pe.Offset, expOffset)
+		testhelper.DiffString(t, id, "part", pe.Part.String(), expPart)
+
+		if !errors.Is(err, expKind) {
+			t.Log(id)
+			t.Errorf("\t: the error should be of kind %s: %v",
+				expKindName, pe.Kind)
+		}
+
+		if !strings.HasPrefix(err.Error(), "bad "+semver.Name+" - ") {
+			t.Log(id)
+			t.Errorf("\t: bad error message: %s", err)
 		}
 	}
 }
@@ -407,3 +464,137 @@ func TestHasIDs(t *testing.T) {
 			tc.sv.HasBuildIDs(), tc.expHasBldIDs)
 	}
 }
+
+func TestBigVersionNumbers(t *testing.T) {
+	const big20 = "99999999999999999999"
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpPanic
+		svStr       string
+		expMajor    int
+		expMajorBig string
+		expMinorBig string
+		expPatchBig string
+		expIncrMaj  string
+		expIncrMin  string
+		expIncrPat  string
+	}{
+		{
+			ID:          testhelper.MkID("small"),
+			svStr:       "v1.2.3",
+			expMajor:    1,
+			expMajorBig: "1",
+			expMinorBig: "2",
+			expPatchBig: "3",
+			expIncrMaj:  "v2.0.0",
+			expIncrMin:  "v1.3.0",
+			expIncrPat:  "v1.2.4",
+		},
+		{
+			ID:          testhelper.MkID("max int"),
+			svStr:       "v9223372036854775807.0.9",
+			expMajor:    9223372036854775807,
+			expMajorBig: "9223372036854775807",
+			expMinorBig: "0",
+			expPatchBig: "9",
+			expIncrMaj:  "v9223372036854775808.0.0",
+			expIncrMin:  "v9223372036854775807.1.0",
+			expIncrPat:  "v9223372036854775807.0.10",
+		},
+		{
+			ID:    testhelper.MkID("too big for an int"),
+			svStr: "v" + big20 + "." + big20 + "." + big20 + "-rc",
+			ExpPanic: testhelper.MkExpPanic(
+				"the major version: "+big20+" is too big for an int",
+				"use MajorBig"),
+			expMajorBig: big20,
+			expMinorBig: big20,
+			expPatchBig: big20,
+			expIncrMaj:  "v1" + strings.Repeat("0", 20) + ".0.0",
+			expIncrMin:  "v" + big20 + ".1" + strings.Repeat("0", 20) + ".0",
+			expIncrPat: "v" + big20 + "." + big20 +
+				".1" + strings.Repeat("0", 20),
+		},
+	}
+
+	for _, tc := range testCases {
+		sv, err := semver.ParseSV(tc.svStr)
+		if err != nil {
+			t.Fatal("Couldn't parse the semver: ", err)
+		}
+
+		testhelper.DiffString(t, tc.IDStr(), "String", sv.String(), tc.svStr)
+		var major int
+
+		panicked, panicVal := testhelper.PanicSafe(func() {
+			major = sv.Major()
+		})
+		if testhelper.CheckExpPanic(t, panicked, panicVal, tc) && !panicked {
+			testhelper.DiffInt(t, tc.IDStr(), "Major", major, tc.expMajor)
+		}
+
+		testhelper.DiffString(t, tc.IDStr(), "MajorBig",
+			sv.MajorBig().String(), tc.expMajorBig)
+		testhelper.DiffString(t, tc.IDStr(), "MinorBig",
+			sv.MinorBig().String(), tc.expMinorBig)
+		testhelper.DiffString(t, tc.IDStr(), "PatchBig",
+			sv.PatchBig().String(), tc.expPatchBig)
+
+		incr := []struct {
+			name   string
+			f      func(*semver.SV)
+			expStr string
+		}{
+			{"IncrMajor", (*semver.SV).IncrMajor, tc.expIncrMaj},
+			{"IncrMinor", (*semver.SV).IncrMinor, tc.expIncrMin},
+			{"IncrPatch", (*semver.SV).IncrPatch, tc.expIncrPat},
+		}
+		for _, i := range incr {
+			next := &semver.SV{}
+			sv.CopyInto(next)
+			i.f(next)
+
+			testhelper.DiffString(t, tc.IDStr(), i.name,
+				next.String(), i.expStr)
+			testhelper.DiffBool(t, tc.IDStr(), i.name+" is greater",
+				semver.Less(sv, next), true)
+		}
+	}
+}
+
+func TestBigNumberPrecedence(t *testing.T) {
+	ordered := []string{
+		"v1.0.0-2",
+		"v1.0.0-10",
+		"v1.0.0-9223372036854775807",
+		"v1.0.0-9223372036854775808",
+		"v1.0.0-99999999999999999999",
+		"v1.0.0-100000000000000000000",
+		"v1.0.0-100000000000000000000.1",
+		"v1.0.0-a",
+		"v1.0.0",
+		"v9.0.0",
+		"v10.0.0",
+		"v9223372036854775807.0.0",
+		"v9223372036854775808.0.0",
+		"v100000000000000000000.0.0",
+	}
+
+	for i, aStr := range ordered {
+		a, err := semver.ParseSV(aStr)
+		if err != nil {
+			t.Fatal("Couldn't parse the semver: ", err)
+		}
+
+		for j, bStr := range ordered {
+			b, err := semver.ParseSV(bStr)
+			if err != nil {
+				t.Fatal("Couldn't parse the semver: ", err)
+			}
+
+			testhelper.DiffInt(t, aStr+" vs "+bStr, "Compare",
+				semver.Compare(a, b), cmp.Compare(i, j))
+		}
+	}
+}
@@ -0,0 +1,364 @@
+package semver
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// These constants are used in building the text sort key
+const (
+	sortKeyNumWidth      = 20
+	sortKeyBigNum        = ":"
+	sortKeyPartSep       = "."
+	sortKeyRelease       = "~"
+	sortKeyPreRel        = "-"
+	sortKeyNumericID     = "0"
+	sortKeyAlphaID       = "1"
+	sortKeyPreRelIDSep   = ","
+	sortKeyPreRelIDsEnd  = "!"
+	sortKeyBuildIDsStart = "+"
+)
+
+// Scan sets the SV from the value read from a database. The value should be
+// a string (or a slice of bytes) holding the SV in the form that ParseSV
+// takes. A NULL value or an empty string clears the SV and it will not be
+// marked as having been set. This satisfies the sql.Scanner interface.
+func (sv *SV) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*sv = SV{}
+
+		return nil
+	case string:
+		return sv.UnmarshalText([]byte(v))
+	case []byte:
+		return sv.UnmarshalText(v)
+	}
+
+	return fmt.Errorf("bad %s - cannot scan a value of type %T", Name, src)
+}
+
+// Value returns the SV as a string to be stored in a database. An SV which
+// has not been set is stored as NULL. This satisfies the driver.Valuer
+// interface.
+func (sv SV) Value() (driver.Value, error) {
+	if !sv.hasBeenSet {
+		return nil, nil
+	}
+
+	return sv.String(), nil
+}
+
+// TextSortKey returns a string which can be compared with the sort key of
+// another SV to give the same order as the Less function. That is, if
+// Less(a, b) then a.TextSortKey() < b.TextSortKey(). The key is made up of:
+//
+//   - the major, minor and patch numbers, each zero-padded to 20 digits and
+//     separated by a '.'. A number with more than 20 digits is written as a
+//     ':' followed by the number of digits, zero-padded to 20 digits, and
+//     then the number
+//   - a '~' if there are no pre-release IDs, otherwise a '-' followed by
+//     the pre-release IDs separated by ',' and terminated by '!'. Each
+//     numeric ID is written as '0' followed by the number, written as for
+//     the major, minor and patch numbers, and each alphanumeric ID as '1'
+//     followed by the ID
+//   - if there are any build IDs, a '+' followed by the build IDs separated
+//     by '.'
+//
+// The build IDs play no part in the precedence of the SV; they are
+// included so that the SV can be reconstructed from the key (see
+// ParseTextSortKey). Two SVs which differ only in their build IDs will
+// have different keys but will sort together.
+//
+// The keys must be compared byte by byte. If they are stored in a database
+// the column must use a binary collation (for instance, COLLATE "C" in
+// PostgreSQL; this is the default in SQLite) otherwise ORDER BY on the
+// column may not give the correct order.
+func (sv SV) TextSortKey() string {
+	var b strings.Builder
+
+	b.WriteString(sortKeyNum(sv.major))
+	b.WriteString(sortKeyPartSep)
+	b.WriteString(sortKeyNum(sv.minor))
+	b.WriteString(sortKeyPartSep)
+	b.WriteString(sortKeyNum(sv.patch))
+
+	if len(sv.preRelIDs) == 0 {
+		b.WriteString(sortKeyRelease)
+	} else {
+		b.WriteString(sortKeyPreRel)
+
+		for i, id := range sv.preRelIDs {
+			if i > 0 {
+				b.WriteString(sortKeyPreRelIDSep)
+			}
+
+			if numericOnlyRE.MatchString(id) {
+				b.WriteString(sortKeyNumericID)
+				b.WriteString(sortKeyNum(vNum(id)))
+			} else {
+				b.WriteString(sortKeyAlphaID)
+				b.WriteString(id)
+			}
+		}
+
+		b.WriteString(sortKeyPreRelIDsEnd)
+	}
+
+	if len(sv.buildIDs) > 0 {
+		b.WriteString(sortKeyBuildIDsStart)
+		b.WriteString(strings.Join(sv.buildIDs, semverPartSeparator))
+	}
+
+	return b.String()
+}
+
+// sortKeyNum returns the number zero-padded to the sort key width. A
+// number with more digits than this is written as a ':' followed by the
+// number of digits, zero-padded, and then the digits.
+func sortKeyNum(n vNum) string {
+	digits := n.String()
+	if len(digits) <= sortKeyNumWidth {
+		return strings.Repeat("0", sortKeyNumWidth-len(digits)) + digits
+	}
+
+	return sortKeyBigNum + sortKeyNum(intVNum(len(digits))) + digits
+}
+
+// cutSortKeyNum removes the leading number from the sort key and returns it
+// and the rest of the key
+func cutSortKeyNum(key, name string) (vNum, string, error) {
+	rest, ok := strings.CutPrefix(key, sortKeyBigNum)
+	if !ok {
+		return cutSortKeyPaddedNum(key, name)
+	}
+
+	l, rest, err := cutSortKeyPaddedNum(rest, name+" length")
+	if err != nil {
+		return "", "", err
+	}
+
+	n, ok := l.toInt()
+	if !ok || n <= sortKeyNumWidth {
+		return "", "", fmt.Errorf("the %s number length (%s) is bad",
+			name, l)
+	}
+
+	if len(rest) < n {
+		return "", "", fmt.Errorf("the %s number is too short", name)
+	}
+
+	v, err := strToVNum(rest[:n], name)
+	if err != nil {
+		return "", "", err
+	}
+
+	return v, rest[n:], nil
+}
+
+// cutSortKeyPaddedNum removes the leading zero-padded number from the sort
+// key and returns it and the rest of the key
+func cutSortKeyPaddedNum(key, name string) (vNum, string, error) {
+	if len(key) < sortKeyNumWidth {
+		return "", "", fmt.Errorf("the %s number is too short", name)
+	}
+
+	for i := range sortKeyNumWidth {
+		if !isDigit(key[i]) {
+			return "", "", fmt.Errorf("the %s number is not all digits", name)
+		}
+	}
+
+	numStr := strings.TrimLeft(key[:sortKeyNumWidth], "0")
+	if numStr == "" {
+		numStr = "0"
+	}
+
+	return vNum(numStr), key[sortKeyNumWidth:], nil
+}
+
+// cutSortKeySep removes the leading separator from the sort key and returns
+// the rest of the key
+func cutSortKeySep(key, sep, desc string) (string, error) {
+	rest, ok := strings.CutPrefix(key, sep)
+	if !ok {
+		return "", fmt.Errorf("%q was expected %s", sep, desc)
+	}
+
+	return rest, nil
+}
+
+// ParseTextSortKey reconstructs an SV from the key returned by TextSortKey.
+// It returns an error if the key is not well-formed.
+func ParseTextSortKey(key string) (*SV, error) {
+	sv, err := parseTextSortKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("bad %s sort key: %q - %w", Name, key, err)
+	}
+
+	return sv, nil
+}
+
+// parseTextSortKey reconstructs an SV from the key returned by TextSortKey
+//
+//nolint:cyclop
+func parseTextSortKey(key string) (*SV, error) {
+	var (
+		major, minor, patch vNum
+		prIDs, buildIDs     []string
+		err                 error
+	)
+
+	rest := key
+
+	if major, rest, err = cutSortKeyNum(rest, "major"); err != nil {
+		return nil, err
+	}
+
+	if rest, err = cutSortKeySep(rest, sortKeyPartSep,
+		"after the major number"); err != nil {
+		return nil, err
+	}
+
+	if minor, rest, err = cutSortKeyNum(rest, "minor"); err != nil {
+		return nil, err
+	}
+
+	if rest, err = cutSortKeySep(rest, sortKeyPartSep,
+		"after the minor number"); err != nil {
+		return nil, err
+	}
+
+	if patch, rest, err = cutSortKeyNum(rest, "patch"); err != nil {
+		return nil, err
+	}
+
+	if prIDs, rest, err = cutSortKeyPreRelIDs(rest); err != nil {
+		return nil, err
+	}
+
+	if rest != "" {
+		if rest, err = cutSortKeySep(rest, sortKeyBuildIDsStart,
+			"before the build IDs"); err != nil {
+			return nil, err
+		}
+
+		buildIDs = strings.Split(rest, semverPartSeparator)
+	}
+
+	return newSV(major, minor, patch, prIDs, buildIDs)
+}
+
+// cutSortKeyPreRelIDs removes the release marker or the pre-release IDs from
+// the start of the sort key and returns the IDs and the rest of the key
+func cutSortKeyPreRelIDs(key string) ([]string, string, error) {
+	if rest, ok := strings.CutPrefix(key, sortKeyRelease); ok {
+		return nil, rest, nil
+	}
+
+	rest, err := cutSortKeySep(key, sortKeyPreRel,
+		"after the patch number")
+	if err != nil {
+		return nil, "", fmt.Errorf("%q or %s", sortKeyRelease, err)
+	}
+
+	prIDs := []string{}
+
+	for {
+		var id string
+
+		switch {
+		case strings.HasPrefix(rest, sortKeyNumericID):
+			var n vNum
+
+			n, rest, err = cutSortKeyNum(rest[1:], "numeric pre-release ID")
+			if err != nil {
+				return nil, "", err
+			}
+
+			id = string(n)
+		case strings.HasPrefix(rest, sortKeyAlphaID):
+			end := strings.IndexAny(rest,
+				sortKeyPreRelIDSep+sortKeyPreRelIDsEnd)
+			if end < 0 {
+				return nil, "",
+					errors.New("the pre-release IDs are not terminated")
+			}
+
+			id, rest = rest[1:end], rest[end:]
+			if numericOnlyRE.MatchString(id) {
+				return nil, "", fmt.Errorf(
+					"the pre-release ID %q is numeric but is not marked so",
+					id)
+			}
+		default:
+			return nil, "", errors.New("a pre-release ID has a bad type")
+		}
+
+		prIDs = append(prIDs, id)
+
+		if rest, ok := strings.CutPrefix(rest, sortKeyPreRelIDsEnd); ok {
+			return prIDs, rest, nil
+		}
+
+		if rest, err = cutSortKeySep(rest, sortKeyPreRelIDSep,
+			"between the pre-release IDs"); err != nil {
+			return nil, "", err
+		}
+	}
+}
+
+// SortableSV is an SV which is stored in a database as its TextSortKey
+// rather than its string form. This means that ORDER BY on the column will
+// give the same order as Less, provided that the column uses a binary
+// collation (see TextSortKey).
+type SortableSV struct {
+	SV
+}
+
+// Scan sets the SV from the sort key read from a database (see
+// ParseTextSortKey). A NULL value or an empty string clears the SV and it
+// will not be marked as having been set. This satisfies the sql.Scanner
+// interface.
+func (ssv *SortableSV) Scan(src any) error {
+	var key string
+
+	switch v := src.(type) {
+	case nil:
+	case string:
+		key = v
+	case []byte:
+		key = string(v)
+	default:
+		return fmt.Errorf("bad %s sort key - cannot scan a value of type %T",
+			Name, src)
+	}
+
+	if key == "" {
+		ssv.SV = SV{}
+
+		return nil
+	}
+
+	sv, err := ParseTextSortKey(key)
+	if err != nil {
+		return err
+	}
+
+	ssv.SV = *sv
+
+	return nil
+}
+
+// Value returns the sort key of the SV to be stored in a database (see
+// TextSortKey). An SV which has not been set is stored as NULL. This
+// satisfies the driver.Valuer interface.
+func (ssv SortableSV) Value() (driver.Value, error) {
+	if !ssv.hasBeenSet {
+		return nil, nil
+	}
+
+	return ssv.TextSortKey(), nil
+}
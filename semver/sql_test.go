@@ -0,0 +1,415 @@
+package semver_test
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/nickwells/semver.mod/v3/semver"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+// fakeDriver is a minimal database driver which stores a single column of
+// values. Any statement with an argument inserts it and any statement
+// without an argument returns all the values sorted byte by byte, as an
+// ORDER BY on a column with a binary collation would.
+type fakeDriver struct {
+	mu   sync.Mutex
+	vals []driver.Value
+}
+
+type (
+	fakeConn struct{ d *fakeDriver }
+	fakeStmt struct{ d *fakeDriver }
+	fakeRows struct{ vals []driver.Value }
+)
+
+var fakeDB = &fakeDriver{}
+
+func init() {
+	sql.Register("semverFake", fakeDB)
+}
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) {
+	return fakeConn{d: d}, nil
+}
+
+func (c fakeConn) Prepare(string) (driver.Stmt, error) {
+	return fakeStmt(c), nil
+}
+
+func (fakeConn) Close() error { return nil }
+
+func (fakeConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("transactions are not supported")
+}
+
+func (fakeStmt) Close() error  { return nil }
+func (fakeStmt) NumInput() int { return -1 }
+
+func (s fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("expected 1 argument, got %d", len(args))
+	}
+
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+
+	s.d.vals = append(s.d.vals, args[0])
+
+	return driver.RowsAffected(1), nil
+}
+
+// fakeKey returns the bytes used to order the values, NULLs sort first
+func fakeKey(v driver.Value) []byte {
+	switch v := v.(type) {
+	case string:
+		return []byte(v)
+	case []byte:
+		return v
+	}
+
+	return nil
+}
+
+func (s fakeStmt) Query([]driver.Value) (driver.Rows, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+
+	vals := slices.Clone(s.d.vals)
+	slices.SortStableFunc(vals, func(a, b driver.Value) int {
+		return bytes.Compare(fakeKey(a), fakeKey(b))
+	})
+
+	return &fakeRows{vals: vals}, nil
+}
+
+func (*fakeRows) Columns() []string { return []string{"v"} }
+func (*fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.vals) == 0 {
+		return io.EOF
+	}
+
+	dest[0], r.vals = r.vals[0], r.vals[1:]
+
+	return nil
+}
+
+func TestScanValue(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		src      any
+		expSVStr string
+		expSet   bool
+	}{
+		{
+			ID:       testhelper.MkID("NULL"),
+			src:      nil,
+			expSVStr: "",
+		},
+		{
+			ID:       testhelper.MkID("string"),
+			src:      "v1.2.3-rc.1+build.7",
+			expSVStr: "v1.2.3-rc.1+build.7",
+			expSet:   true,
+		},
+		{
+			ID:       testhelper.MkID("bytes"),
+			src:      []byte("v1.2.3"),
+			expSVStr: "v1.2.3",
+			expSet:   true,
+		},
+		{
+			ID:  testhelper.MkID("bad - not a semver"),
+			src: "1.2.3",
+			ExpErr: testhelper.MkExpErr(
+				"bad " + semver.Name + " - it does not start with a 'v'"),
+		},
+		{
+			ID:  testhelper.MkID("bad - wrong type"),
+			src: 42,
+			ExpErr: testhelper.MkExpErr(
+				"bad " + semver.Name + " - cannot scan a value of type int"),
+		},
+	}
+
+	for _, tc := range testCases {
+		sv := semver.NewSVOrPanic(9, 9, 9, nil, nil)
+
+		err := sv.Scan(tc.src)
+		if !testhelper.CheckExpErr(t, err, tc) || err != nil {
+			continue
+		}
+
+		testhelper.DiffString(t, tc.IDStr(), "SV", sv.String(), tc.expSVStr)
+		testhelper.DiffBool(t, tc.IDStr(), "has been set",
+			sv.HasBeenSet(), tc.expSet)
+
+		val, err := sv.Value()
+		if err != nil {
+			t.Log(tc.IDStr())
+			t.Errorf("\t: unexpected error from Value(): %s", err)
+
+			continue
+		}
+
+		var expVal driver.Value
+		if tc.expSet {
+			expVal = tc.expSVStr
+		}
+
+		if val != expVal {
+			t.Log(tc.IDStr())
+			t.Logf("\t: expected: %#v", expVal)
+			t.Logf("\t:      got: %#v", val)
+			t.Errorf("\t: bad Value()")
+		}
+	}
+}
+
+func TestTextSortKey(t *testing.T) {
+	z17 := "00000000000000000"
+
+	testCases := []struct {
+		testhelper.ID
+		svStr  string
+		expKey string
+	}{
+		{
+			ID:     testhelper.MkID("release"),
+			svStr:  "v1.2.3",
+			expKey: z17 + "001." + z17 + "002." + z17 + "003~",
+		},
+		{
+			ID:    testhelper.MkID("pre-release and build IDs"),
+			svStr: "v10.0.300-rc.12.a-b+build.007",
+			expKey: z17 + "010." + z17 + "000." + z17 + "300" +
+				"-1rc,0" + z17 + "012,1a-b!" +
+				"+build.007",
+		},
+		{
+			ID: testhelper.MkID("big numbers"),
+			svStr: "v123456789012345678901.99999999999999999999.0" +
+				"-1000000000000000000000",
+			expKey: ":" + z17 + "021" + "123456789012345678901." +
+				"99999999999999999999." + z17 + "000" +
+				"-0:" + z17 + "022" + "1000000000000000000000!",
+		},
+	}
+
+	for _, tc := range testCases {
+		sv, err := semver.ParseSV(tc.svStr)
+		if err != nil {
+			t.Fatal("Couldn't parse the semver: ", err)
+		}
+
+		key := sv.TextSortKey()
+		testhelper.DiffString(t, tc.IDStr(), "sort key", key, tc.expKey)
+
+		keySV, err := semver.ParseTextSortKey(key)
+		if err != nil {
+			t.Log(tc.IDStr())
+			t.Errorf("\t: unexpected error from ParseTextSortKey: %s", err)
+
+			continue
+		}
+
+		testhelper.DiffString(t, tc.IDStr(), "parsed sort key",
+			keySV.String(), tc.svStr)
+	}
+}
+
+func TestParseTextSortKey(t *testing.T) {
+	z17 := "00000000000000000"
+	core := z17 + "001." + z17 + "002." + z17 + "003"
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		key string
+	}{
+		{
+			ID:  testhelper.MkID("good"),
+			key: core + "-1rc,0" + z17 + "012!+b",
+		},
+		{
+			ID:     testhelper.MkID("bad - empty"),
+			ExpErr: testhelper.MkExpErr("the major number is too short"),
+		},
+		{
+			ID:     testhelper.MkID("bad - non-digit"),
+			key:    z17 + "0+1." + z17 + "002." + z17 + "003~",
+			ExpErr: testhelper.MkExpErr("the major number is not all digits"),
+		},
+		{
+			ID:     testhelper.MkID("bad - missing separator"),
+			key:    z17 + "001" + z17 + "002." + z17 + "003~",
+			ExpErr: testhelper.MkExpErr(`"." was expected after the major`),
+		},
+		{
+			ID:  testhelper.MkID("bad - big number length"),
+			key: ":" + z17 + "020" + "12345678901234567890." + core[21:],
+			ExpErr: testhelper.MkExpErr(
+				"the major number length (20) is bad"),
+		},
+		{
+			ID:     testhelper.MkID("bad - big number too short"),
+			key:    ":" + z17 + "025" + "123",
+			ExpErr: testhelper.MkExpErr("the major number is too short"),
+		},
+		{
+			ID:  testhelper.MkID("bad - big number leading zero"),
+			key: ":" + z17 + "021" + "012345678901234567890." + core[21:],
+			ExpErr: testhelper.MkExpErr(
+				`the major version: "012345678901234567890" has a leading 0`),
+		},
+		{
+			ID:  testhelper.MkID("bad - no release marker"),
+			key: core,
+			ExpErr: testhelper.MkExpErr(
+				`"~" or "-" was expected after the patch`),
+		},
+		{
+			ID:  testhelper.MkID("bad - unterminated pre-release IDs"),
+			key: core + "-1rc",
+			ExpErr: testhelper.MkExpErr(
+				"the pre-release IDs are not terminated"),
+		},
+		{
+			ID:     testhelper.MkID("bad - pre-release ID type"),
+			key:    core + "-2rc!",
+			ExpErr: testhelper.MkExpErr("a pre-release ID has a bad type"),
+		},
+		{
+			ID:  testhelper.MkID("bad - numeric ID marked as alphanumeric"),
+			key: core + "-112!",
+			ExpErr: testhelper.MkExpErr(
+				`the pre-release ID "12" is numeric but is not marked so`),
+		},
+		{
+			ID:  testhelper.MkID("bad - trailing text"),
+			key: core + "~x",
+			ExpErr: testhelper.MkExpErr(
+				`"+" was expected before the build IDs`),
+		},
+		{
+			ID:  testhelper.MkID("bad - empty build ID"),
+			key: core + "~+a..b",
+			ExpErr: testhelper.MkExpErr(
+				"the Build ID: '' must be " + semver.GoodIDDesc),
+		},
+	}
+
+	for _, tc := range testCases {
+		_, err := semver.ParseTextSortKey(tc.key)
+		testhelper.CheckExpErr(t, err, tc)
+	}
+}
+
+func TestSortableSVOrderBy(t *testing.T) {
+	expOrder := []string{
+		"",
+		"v0.0.0-0",
+		"v0.0.0",
+		"v0.9.0",
+		"v0.10.0",
+		"v1.0.0-0",
+		"v1.0.0-2",
+		"v1.0.0-10",
+		"v1.0.0-10.a",
+		"v1.0.0-99999999999999999999999",
+		"v1.0.0-A",
+		"v1.0.0-alpha",
+		"v1.0.0-alpha.1",
+		"v1.0.0-alpha.beta",
+		"v1.0.0-alpha0",
+		"v1.0.0-beta",
+		"v1.0.0-beta.2",
+		"v1.0.0-beta.11",
+		"v1.0.0-rc.1",
+		"v1.0.0",
+		"v1.0.0+build.1",
+		"v1.0.1",
+		"v1.2.0",
+		"v1.10.0",
+		"v2.0.0",
+		"v10.0.0",
+		"v99999999999999999999.0.0",
+		"v100000000000000000000.0.0",
+		"v123456789012345678901234567890.0.0",
+	}
+
+	db, err := sql.Open("semverFake", "")
+	if err != nil {
+		t.Fatal("Couldn't open the fake database: ", err)
+	}
+
+	defer db.Close()
+
+	// insert them in an order different from the expected one
+	for i := range expOrder {
+		svStr := expOrder[(i*7)%len(expOrder)]
+
+		var ssv semver.SortableSV
+		if svStr != "" {
+			sv, err := semver.ParseSV(svStr)
+			if err != nil {
+				t.Fatal("Couldn't parse the semver: ", err)
+			}
+
+			ssv.SV = *sv
+		}
+
+		if _, err := db.Exec("INSERT", ssv); err != nil {
+			t.Fatal("Couldn't insert the semver: ", err)
+		}
+	}
+
+	rows, err := db.Query("SELECT")
+	if err != nil {
+		t.Fatal("Couldn't query the fake database: ", err)
+	}
+	defer rows.Close()
+
+	got := []string{}
+
+	for rows.Next() {
+		var ssv semver.SortableSV
+		if err := rows.Scan(&ssv); err != nil {
+			t.Fatal("Couldn't scan the row: ", err)
+		}
+
+		got = append(got, ssv.String())
+	}
+
+	if err := rows.Err(); err != nil {
+		t.Fatal("Error reading the rows: ", err)
+	}
+
+	testhelper.DiffStringSlice(t, "ORDER BY", "sorted versions",
+		got, expOrder)
+
+	// check that the expected order agrees with Less
+	for i := 2; i < len(expOrder); i++ {
+		a, errA := semver.ParseSV(expOrder[i-1])
+		b, errB := semver.ParseSV(expOrder[i])
+
+		if errA != nil || errB != nil {
+			t.Fatal("Couldn't parse the semvers: ", errA, errB)
+		}
+
+		if semver.Less(b, a) {
+			t.Errorf("the expected order is wrong: %s should be before %s",
+				b, a)
+		}
+	}
+}
@@ -1,5 +1,24 @@
 package semver
 
+import "slices"
+
+// PreRelPolicy controls whether versions with pre-release IDs are eligible
+// to be chosen when selecting versions from an SVList which satisfy a
+// Constraint
+type PreRelPolicy int
+
+const (
+	// PreRelByConstraint applies the Constraint's own rules for pre-release
+	// versions: they are only eligible if some clause of the Constraint has
+	// pre-release IDs and the same major, minor and patch versions
+	PreRelByConstraint PreRelPolicy = iota
+	// PreRelAllowed makes any pre-release version eligible if it lies within
+	// the bounds of the Constraint
+	PreRelAllowed
+	// PreRelExcluded makes no pre-release version eligible
+	PreRelExcluded
+)
+
 // SVList is a slice of semvers. It provides the base from which to hang the
 // sorting methods
 type SVList []*SV
@@ -19,3 +38,95 @@ func (svl SVList) Len() int {
 func (svl SVList) Swap(i, j int) {
 	svl[i], svl[j] = svl[j], svl[i]
 }
+
+// Max returns the highest version in the list or nil if the list is
+// empty. If more than one version has the highest precedence the first is
+// returned.
+func (svl SVList) Max() *SV {
+	var highest *SV
+
+	for _, sv := range svl {
+		if highest == nil || Less(highest, sv) {
+			highest = sv
+		}
+	}
+
+	return highest
+}
+
+// Min returns the lowest version in the list or nil if the list is
+// empty. If more than one version has the lowest precedence the first is
+// returned.
+func (svl SVList) Min() *SV {
+	var lowest *SV
+
+	for _, sv := range svl {
+		if lowest == nil || Less(sv, lowest) {
+			lowest = sv
+		}
+	}
+
+	return lowest
+}
+
+// Filter returns a new SVList holding those versions for which the
+// predicate returns true. The order of the versions is preserved.
+func (svl SVList) Filter(pred func(*SV) bool) SVList {
+	filtered := SVList{}
+
+	for _, sv := range svl {
+		if pred(sv) {
+			filtered = append(filtered, sv)
+		}
+	}
+
+	return filtered
+}
+
+// matchPred returns a predicate which reports whether a version satisfies
+// the constraint according to the pre-release policy
+func matchPred(c *Constraint, p PreRelPolicy) func(*SV) bool {
+	switch p {
+	case PreRelAllowed:
+		vs := NewVersionSet(c)
+
+		return func(sv *SV) bool {
+			if sv.HasPreRelIDs() {
+				return vs.Contains(sv)
+			}
+
+			return c.Check(sv)
+		}
+	case PreRelExcluded:
+		return func(sv *SV) bool {
+			return !sv.HasPreRelIDs() && c.Check(sv)
+		}
+	}
+
+	return c.Check
+}
+
+// Matching returns a new SVList holding those versions which satisfy the
+// constraint, with pre-release versions being eligible according to the
+// policy. The versions are sorted in ascending order.
+func (svl SVList) Matching(c *Constraint, p PreRelPolicy) SVList {
+	matches := svl.Filter(matchPred(c, p))
+	slices.SortStableFunc(matches, Compare)
+
+	return matches
+}
+
+// HighestMatching returns the highest version in the list which satisfies
+// the constraint, with pre-release versions being eligible according to the
+// policy. It returns nil if no version matches.
+func (svl SVList) HighestMatching(c *Constraint, p PreRelPolicy) *SV {
+	return svl.Filter(matchPred(c, p)).Max()
+}
+
+// LowestMatching returns the lowest version in the list which satisfies the
+// constraint, with pre-release versions being eligible according to the
+// policy. It returns nil if no version matches. This is the version that
+// would be chosen by Minimal Version Selection, as used by Go modules.
+func (svl SVList) LowestMatching(c *Constraint, p PreRelPolicy) *SV {
+	return svl.Filter(matchPred(c, p)).Min()
+}
@@ -0,0 +1,165 @@
+package semver_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nickwells/semver.mod/v3/semver"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+// mkSVList parses the strings and returns them as an SVList
+func mkSVList(t *testing.T, svStrs ...string) semver.SVList {
+	t.Helper()
+
+	svl := semver.SVList{}
+
+	for _, s := range svStrs {
+		sv, err := semver.ParseSV(s)
+		if err != nil {
+			t.Fatal("Couldn't parse the semver: ", err)
+		}
+
+		svl = append(svl, sv)
+	}
+
+	return svl
+}
+
+// svStr returns the string form of the SV or "nil" if it is nil
+func svStr(sv *semver.SV) string {
+	if sv == nil {
+		return "nil"
+	}
+
+	return sv.String()
+}
+
+// svListStr returns the string form of the SVList
+func svListStr(svl semver.SVList) string {
+	strs := []string{}
+	for _, sv := range svl {
+		strs = append(strs, svStr(sv))
+	}
+
+	return strings.Join(strs, ", ")
+}
+
+func TestSVListMaxMin(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		svl    []string
+		expMax string
+		expMin string
+	}{
+		{
+			ID:     testhelper.MkID("empty"),
+			expMax: "nil",
+			expMin: "nil",
+		},
+		{
+			ID:     testhelper.MkID("one entry"),
+			svl:    []string{"v1.2.3"},
+			expMax: "v1.2.3",
+			expMin: "v1.2.3",
+		},
+		{
+			ID:     testhelper.MkID("several entries"),
+			svl:    []string{"v1.2.3", "v2.0.0-rc.1", "v0.9.0", "v1.10.0"},
+			expMax: "v2.0.0-rc.1",
+			expMin: "v0.9.0",
+		},
+		{
+			ID:     testhelper.MkID("equal precedence - first is chosen"),
+			svl:    []string{"v1.0.0+a", "v1.0.0+b", "v0.1.0+c", "v0.1.0+d"},
+			expMax: "v1.0.0+a",
+			expMin: "v0.1.0+c",
+		},
+	}
+
+	for _, tc := range testCases {
+		svl := mkSVList(t, tc.svl...)
+		testhelper.DiffString(t, tc.IDStr(), "max", svStr(svl.Max()), tc.expMax)
+		testhelper.DiffString(t, tc.IDStr(), "min", svStr(svl.Min()), tc.expMin)
+	}
+}
+
+func TestSVListFilter(t *testing.T) {
+	svl := mkSVList(t, "v1.2.3", "v2.0.0-rc.1", "v0.9.0", "v1.10.0")
+
+	testhelper.DiffString(t, "Filter", "release versions",
+		svListStr(svl.Filter(func(sv *semver.SV) bool {
+			return !sv.HasPreRelIDs()
+		})),
+		"v1.2.3, v0.9.0, v1.10.0")
+	testhelper.DiffString(t, "Filter", "no versions",
+		svListStr(svl.Filter(func(_ *semver.SV) bool { return false })),
+		"")
+}
+
+func TestSVListMatching(t *testing.T) {
+	svl := mkSVList(t,
+		"v1.4.0", "v1.2.0", "v2.0.0", "v1.5.0-rc.1", "v1.3.0-beta",
+		"v1.3.0", "v0.9.0", "v1.3.0-alpha")
+
+	testCases := []struct {
+		testhelper.ID
+		cStr       string
+		policy     semver.PreRelPolicy
+		expMatches string
+		expHighest string
+		expLowest  string
+	}{
+		{
+			ID:         testhelper.MkID("caret - by constraint"),
+			cStr:       "^1.2.0",
+			policy:     semver.PreRelByConstraint,
+			expMatches: "v1.2.0, v1.3.0, v1.4.0",
+			expHighest: "v1.4.0",
+			expLowest:  "v1.2.0",
+		},
+		{
+			ID:     testhelper.MkID("caret - pre-releases allowed"),
+			cStr:   "^1.2.0",
+			policy: semver.PreRelAllowed,
+			expMatches: "v1.2.0, v1.3.0-alpha, v1.3.0-beta, v1.3.0," +
+				" v1.4.0, v1.5.0-rc.1",
+			expHighest: "v1.5.0-rc.1",
+			expLowest:  "v1.2.0",
+		},
+		{
+			ID:         testhelper.MkID("pre-release named - by constraint"),
+			cStr:       ">=1.3.0-beta <2.0.0",
+			policy:     semver.PreRelByConstraint,
+			expMatches: "v1.3.0-beta, v1.3.0, v1.4.0",
+			expHighest: "v1.4.0",
+			expLowest:  "v1.3.0-beta",
+		},
+		{
+			ID:         testhelper.MkID("pre-release named - excluded"),
+			cStr:       ">=1.3.0-beta <2.0.0",
+			policy:     semver.PreRelExcluded,
+			expMatches: "v1.3.0, v1.4.0",
+			expHighest: "v1.4.0",
+			expLowest:  "v1.3.0",
+		},
+		{
+			ID:         testhelper.MkID("no matches"),
+			cStr:       ">=3.0.0",
+			policy:     semver.PreRelAllowed,
+			expMatches: "",
+			expHighest: "nil",
+			expLowest:  "nil",
+		},
+	}
+
+	for _, tc := range testCases {
+		c := semver.ParseConstraintOrPanic(tc.cStr)
+		testhelper.DiffString(t, tc.IDStr(), "matches",
+			svListStr(svl.Matching(c, tc.policy)), tc.expMatches)
+		testhelper.DiffString(t, tc.IDStr(), "highest matching",
+			svStr(svl.HighestMatching(c, tc.policy)), tc.expHighest)
+		testhelper.DiffString(t, tc.IDStr(), "lowest matching",
+			svStr(svl.LowestMatching(c, tc.policy)), tc.expLowest)
+	}
+}
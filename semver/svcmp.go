@@ -1,17 +1,19 @@
 package semver
 
-import "strconv"
+import "cmp"
 
-// lessPRIDs compares the preRelIDs of the two semver values
+// comparePRIDs compares the preRelIDs of the two semver values. It returns
+// a negative number if a is less than b, a positive number if a is greater
+// than b and zero if they have the same precedence
 //
 //nolint:cyclop
-func lessPRIDs(a, b *SV) bool {
+func comparePRIDs(a, b *SV) int {
 	if len(a.preRelIDs) > 0 && len(b.preRelIDs) == 0 {
-		return true
+		return -1
 	}
 
 	if len(a.preRelIDs) == 0 && len(b.preRelIDs) > 0 {
-		return false
+		return 1
 	}
 
 	for i, aID := range a.preRelIDs {
@@ -23,74 +25,98 @@ func lessPRIDs(a, b *SV) bool {
 
 		if goodNumericRE.MatchString(aID) {
 			if goodNumericRE.MatchString(bID) {
-				aAsNum, _ := strconv.Atoi(aID)
-				bAsNum, _ := strconv.Atoi(bID)
-
-				if aAsNum < bAsNum {
-					return true
-				}
-
-				if aAsNum > bAsNum {
-					return false
+				if c := cmpDigits(aID, bID); c != 0 {
+					return c
 				}
 			} else {
-				return true
+				return -1
 			}
 		} else if goodNumericRE.MatchString(bID) {
-			return false
-		} else if aID < bID {
-			return true
-		} else if aID > bID {
-			return false
+			return 1
+		} else if c := cmp.Compare(aID, bID); c != 0 {
+			return c
 		}
 	}
 
-	return len(a.preRelIDs) < len(b.preRelIDs)
+	return cmp.Compare(len(a.preRelIDs), len(b.preRelIDs))
 }
 
-// Less returns true if a is less than b according to the ordering rules for
-// semantic versions given in the Semantic Versioning Specification v2.0.0
-// (spec item 11)
-func Less(a, b *SV) bool {
-	if a.major < b.major {
-		return true
+// ComparePrecedence compares the two SVs according to the ordering rules
+// for semantic versions given in the Semantic Versioning Specification
+// v2.0.0 (spec item 11). It returns a negative number if a is less than b,
+// a positive number if a is greater than b and zero if they have the same
+// precedence. Note that, as per spec item 10, build IDs are ignored so two
+// SVs differing only in their build IDs have the same precedence.
+func ComparePrecedence(a, b *SV) int {
+	if c := compareCore(a, b); c != 0 {
+		return c
 	}
 
-	if a.major > b.major {
-		return false
-	}
+	return comparePRIDs(a, b)
+}
 
-	if a.minor < b.minor {
-		return true
+// compareCore compares the major, minor and patch version numbers of the
+// two SVs
+func compareCore(a, b *SV) int {
+	if c := cmpVNum(a.major, b.major); c != 0 {
+		return c
 	}
 
-	if a.minor > b.minor {
-		return false
+	if c := cmpVNum(a.minor, b.minor); c != 0 {
+		return c
 	}
 
-	if a.patch < b.patch {
-		return true
-	}
+	return cmpVNum(a.patch, b.patch)
+}
 
-	if a.patch > b.patch {
-		return false
-	}
+// Compare compares the two SVs by precedence, it is the same as
+// ComparePrecedence. It is suitable for passing to slices.SortFunc.
+func Compare(a, b *SV) int {
+	return ComparePrecedence(a, b)
+}
 
-	return lessPRIDs(a, b)
+// EqualPrecedence returns true if the two SVs have the same precedence,
+// that is, they are identical apart from any build IDs
+func EqualPrecedence(a, b *SV) bool {
+	return ComparePrecedence(a, b) == 0
 }
 
-// Equals compares the two SemVers and returns true if they are identical,
-// false otherwise
-func Equals(a, b *SV) bool {
-	if a.major != b.major {
-		return false
+// CompareWithBuild compares the two SVs first by precedence and then, if
+// they have the same precedence, by their build IDs. The build IDs are
+// compared in turn in lexical order and if one set of build IDs is a
+// prefix of the other then the shorter one is less. This gives a total
+// order which can be used for deterministic sorting. It only returns zero
+// if Equals would return true.
+func CompareWithBuild(a, b *SV) int {
+	if c := ComparePrecedence(a, b); c != 0 {
+		return c
 	}
 
-	if a.minor != b.minor {
-		return false
+	for i, aID := range a.buildIDs {
+		if i >= len(b.buildIDs) {
+			break
+		}
+
+		if c := cmp.Compare(aID, b.buildIDs[i]); c != 0 {
+			return c
+		}
 	}
 
-	if a.patch != b.patch {
+	return cmp.Compare(len(a.buildIDs), len(b.buildIDs))
+}
+
+// Less returns true if a is less than b according to the ordering rules for
+// semantic versions given in the Semantic Versioning Specification v2.0.0
+// (spec item 11)
+func Less(a, b *SV) bool {
+	return ComparePrecedence(a, b) < 0
+}
+
+// Equals compares the two SemVers and returns true if they are identical,
+// false otherwise. Note that this compares the build IDs as well, see
+// EqualPrecedence for a comparison which ignores them
+func Equals(a, b *SV) bool {
+	if compareCore(a, b) != 0 {
 		return false
 	}
 
@@ -1,6 +1,7 @@
 package semver_test
 
 import (
+	"slices"
 	"testing"
 
 	"github.com/nickwells/semver.mod/v3/semver"
@@ -222,3 +223,139 @@ func TestEquals(t *testing.T) {
 		}
 	}
 }
+
+func TestCompare(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		a, b         string
+		expCmp       int
+		expCmpWBuild int
+	}{
+		{
+			ID:           testhelper.MkID("equal"),
+			a:            "v1.2.3",
+			b:            "v1.2.3",
+			expCmp:       0,
+			expCmpWBuild: 0,
+		},
+		{
+			ID:           testhelper.MkID("major differs"),
+			a:            "v1.2.3",
+			b:            "v2.0.0",
+			expCmp:       -1,
+			expCmpWBuild: -1,
+		},
+		{
+			ID:           testhelper.MkID("minor differs"),
+			a:            "v1.3.0",
+			b:            "v1.2.3",
+			expCmp:       1,
+			expCmpWBuild: 1,
+		},
+		{
+			ID:           testhelper.MkID("patch differs"),
+			a:            "v1.2.3",
+			b:            "v1.2.4",
+			expCmp:       -1,
+			expCmpWBuild: -1,
+		},
+		{
+			ID:           testhelper.MkID("pre-release is less than release"),
+			a:            "v1.2.3",
+			b:            "v1.2.3-rc.1",
+			expCmp:       1,
+			expCmpWBuild: 1,
+		},
+		{
+			ID:           testhelper.MkID("pre-release IDs differ"),
+			a:            "v1.2.3-rc.1",
+			b:            "v1.2.3-rc.11",
+			expCmp:       -1,
+			expCmpWBuild: -1,
+		},
+		{
+			ID:           testhelper.MkID("build IDs differ"),
+			a:            "v1.2.3+b",
+			b:            "v1.2.3+a",
+			expCmp:       0,
+			expCmpWBuild: 1,
+		},
+		{
+			ID:           testhelper.MkID("fewer build IDs"),
+			a:            "v1.2.3+a",
+			b:            "v1.2.3+a.b",
+			expCmp:       0,
+			expCmpWBuild: -1,
+		},
+		{
+			ID:           testhelper.MkID("no build IDs"),
+			a:            "v1.2.3+a",
+			b:            "v1.2.3",
+			expCmp:       0,
+			expCmpWBuild: 1,
+		},
+		{
+			ID:           testhelper.MkID("build IDs differ, precedence doesn't"),
+			a:            "v1.2.3+a",
+			b:            "v1.2.4+0",
+			expCmp:       -1,
+			expCmpWBuild: -1,
+		},
+	}
+
+	for _, tc := range testCases {
+		a, err := semver.ParseSV(tc.a)
+		if err != nil {
+			t.Fatal("Couldn't parse the semver: ", err)
+		}
+
+		b, err := semver.ParseSV(tc.b)
+		if err != nil {
+			t.Fatal("Couldn't parse the semver: ", err)
+		}
+
+		testhelper.DiffInt(t, tc.IDStr(), "Compare(a, b)",
+			semver.Compare(a, b), tc.expCmp)
+		testhelper.DiffInt(t, tc.IDStr(), "Compare(b, a)",
+			semver.Compare(b, a), -tc.expCmp)
+		testhelper.DiffInt(t, tc.IDStr(), "ComparePrecedence(a, b)",
+			semver.ComparePrecedence(a, b), tc.expCmp)
+		testhelper.DiffBool(t, tc.IDStr(), "EqualPrecedence(a, b)",
+			semver.EqualPrecedence(a, b), tc.expCmp == 0)
+		testhelper.DiffBool(t, tc.IDStr(), "Less(a, b)",
+			semver.Less(a, b), tc.expCmp < 0)
+		testhelper.DiffInt(t, tc.IDStr(), "CompareWithBuild(a, b)",
+			semver.CompareWithBuild(a, b), tc.expCmpWBuild)
+		testhelper.DiffInt(t, tc.IDStr(), "CompareWithBuild(b, a)",
+			semver.CompareWithBuild(b, a), -tc.expCmpWBuild)
+		testhelper.DiffBool(t, tc.IDStr(), "Equals(a, b)",
+			semver.Equals(a, b), tc.expCmpWBuild == 0)
+	}
+}
+
+func TestSortFunc(t *testing.T) {
+	svl := semver.SVList{
+		semver.NewSVOrPanic(1, 0, 0, nil, []string{"b"}),
+		semver.NewSVOrPanic(1, 0, 0, []string{"rc", "1"}, nil),
+		semver.NewSVOrPanic(0, 9, 0, nil, nil),
+		semver.NewSVOrPanic(1, 0, 0, nil, []string{"a"}),
+		semver.NewSVOrPanic(1, 0, 0, []string{"beta"}, nil),
+	}
+
+	slices.SortFunc(svl, semver.CompareWithBuild)
+
+	exp := []string{
+		"v0.9.0",
+		"v1.0.0-beta",
+		"v1.0.0-rc.1",
+		"v1.0.0+a",
+		"v1.0.0+b",
+	}
+
+	act := []string{}
+	for _, sv := range svl {
+		act = append(act, sv.String())
+	}
+
+	testhelper.DiffStringSlice(t, "SortFunc", "sorted semvers", act, exp)
+}
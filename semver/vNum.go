@@ -0,0 +1,135 @@
+package semver
+
+import (
+	"cmp"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// vNum holds a version number as a string of decimal digits with no leading
+// zeros. This allows version numbers of any size. The empty string is taken
+// to be zero so that the zero value of an SV has a version of v0.0.0.
+type vNum string
+
+// intVNum returns the vNum for the integer which must not be negative
+func intVNum(i int) vNum {
+	return vNum(strconv.Itoa(i))
+}
+
+// String returns the version number as a string
+func (n vNum) String() string {
+	if n == "" {
+		return "0"
+	}
+
+	return string(n)
+}
+
+// isZero returns true if the version number is zero
+func (n vNum) isZero() bool {
+	return n == "" || n == "0"
+}
+
+// cmpDigits compares two strings of decimal digits, neither of which has a
+// leading zero, in numeric order. A longer string is a bigger number and
+// strings of the same length are compared lexically.
+func cmpDigits(a, b string) int {
+	if c := cmp.Compare(len(a), len(b)); c != 0 {
+		return c
+	}
+
+	return strings.Compare(a, b)
+}
+
+// cmpVNum compares the two version numbers, returning a negative number if
+// a is less than b, a positive number if a is greater than b and zero if
+// they are equal
+func cmpVNum(a, b vNum) int {
+	return cmpDigits(a.String(), b.String())
+}
+
+// cmpInt compares the version number with the integer
+func (n vNum) cmpInt(i int) int {
+	if i < 0 {
+		return 1
+	}
+
+	return cmpVNum(n, intVNum(i))
+}
+
+// incr returns the version number plus one
+func (n vNum) incr() vNum {
+	digits := []byte(n.String())
+
+	for i := len(digits) - 1; i >= 0; i-- {
+		if digits[i] != '9' {
+			digits[i]++
+
+			return vNum(digits)
+		}
+
+		digits[i] = '0'
+	}
+
+	return vNum("1" + string(digits))
+}
+
+// decr returns the version number minus one. Zero is returned unchanged.
+func (n vNum) decr() vNum {
+	if n.isZero() {
+		return n
+	}
+
+	digits := []byte(n)
+
+	for i := len(digits) - 1; i >= 0; i-- {
+		if digits[i] != '0' {
+			digits[i]--
+
+			break
+		}
+
+		digits[i] = '9'
+	}
+
+	if len(digits) > 1 && digits[0] == '0' {
+		digits = digits[1:]
+	}
+
+	return vNum(digits)
+}
+
+// toInt returns the version number as an int and true or, if it is too big
+// to fit in an int, -1 and false
+func (n vNum) toInt() (int, bool) {
+	i, err := strconv.Atoi(n.String())
+	if err != nil {
+		return -1, false
+	}
+
+	return i, true
+}
+
+// mustInt returns the version number as an int. It panics if the number is
+// too big to fit in an int; the panic message uses the name of the version
+// number and of the method which gives it as a big.Int.
+func (n vNum) mustInt(name, bigMethod string) int {
+	i, ok := n.toInt()
+	if !ok {
+		panic(fmt.Sprintf("the %s version: %s is too big for an int - use %s",
+			name, n, bigMethod))
+	}
+
+	return i
+}
+
+// toBig returns the version number as a new big.Int
+func (n vNum) toBig() *big.Int {
+	const base = 10
+
+	b, _ := new(big.Int).SetString(n.String(), base)
+
+	return b
+}
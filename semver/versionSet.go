@@ -0,0 +1,276 @@
+package semver
+
+import (
+	"slices"
+	"strings"
+)
+
+// interval represents a contiguous range of versions. The lower bound is
+// inclusive and the upper bound is exclusive. A nil upper bound means that
+// the interval has no upper bound.
+type interval struct {
+	lo *SV
+	hi *SV
+}
+
+// minSV returns the lowest possible version: v0.0.0-0
+func minSV() *SV {
+	sv := mkReleaseSV("0", "0", "0")
+	sv.preRelIDs = []string{"0"}
+
+	return sv
+}
+
+// precedenceOnly returns a copy of the SV with the build IDs removed
+func precedenceOnly(sv *SV) *SV {
+	pSV := mkReleaseSV(sv.major, sv.minor, sv.patch)
+	pSV.preRelIDs = slices.Clone(sv.preRelIDs)
+
+	return pSV
+}
+
+// successor returns the lowest version which is greater than the SV. For a
+// version with pre-release IDs this is the same version with an extra
+// pre-release ID of "0". For a release version it is the next patch
+// version with a pre-release ID of "0".
+func successor(sv *SV) *SV {
+	if sv.HasPreRelIDs() {
+		succ := precedenceOnly(sv)
+		succ.preRelIDs = append(succ.preRelIDs, "0")
+
+		return succ
+	}
+
+	succ := mkReleaseSV(sv.major, sv.minor, sv.patch.incr())
+	succ.preRelIDs = []string{"0"}
+
+	return succ
+}
+
+// isEmpty returns true if the interval contains no versions
+func (i interval) isEmpty() bool {
+	return i.hi != nil && Compare(i.lo, i.hi) >= 0
+}
+
+// contains returns true if the interval contains the SV
+func (i interval) contains(sv *SV) bool {
+	return Compare(i.lo, sv) <= 0 &&
+		(i.hi == nil || Compare(sv, i.hi) < 0)
+}
+
+// String returns a string representation of the interval
+func (i interval) String() string {
+	if i.hi != nil && Compare(i.hi, successor(i.lo)) == 0 {
+		return comparator{op: opEQ, sv: i.lo}.String()
+	}
+
+	parts := []string{}
+
+	if Compare(i.lo, minSV()) != 0 || i.hi == nil {
+		parts = append(parts, comparator{op: opGE, sv: i.lo}.String())
+	}
+
+	if i.hi != nil {
+		parts = append(parts, comparator{op: opLT, sv: i.hi}.String())
+	}
+
+	return strings.Join(parts, constraintJoinClauses)
+}
+
+// compareHi compares two upper bounds, a nil bound is greater than any
+// other
+func compareHi(a, b *SV) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return 1
+	case b == nil:
+		return -1
+	}
+
+	return Compare(a, b)
+}
+
+// VersionSet represents a set of versions. Unlike a Constraint it can be
+// combined with other VersionSets to form their intersection or union and it
+// can be complemented. It can also be checked for emptiness so that
+// conflicting requirements can be detected without needing to enumerate any
+// versions.
+//
+// A VersionSet includes every version, with or without pre-release IDs, that
+// lies between its bounds, the versions being ordered by precedence. It
+// does not apply the special rule for pre-release versions that a
+// Constraint uses. This means that a VersionSet made from a Constraint
+// will contain every version which satisfies the Constraint and may also
+// contain some pre-release versions which do not. So if the VersionSet is
+// empty then no version can satisfy the Constraint.
+//
+// Build IDs play no part in the ordering of versions and so are ignored.
+type VersionSet struct {
+	intervals []interval
+}
+
+// AllVersions returns a VersionSet holding every version
+func AllVersions() VersionSet {
+	return VersionSet{intervals: []interval{{lo: minSV()}}}
+}
+
+// NoVersions returns an empty VersionSet
+func NoVersions() VersionSet {
+	return VersionSet{}
+}
+
+// mkVersionSet returns a VersionSet made from the intervals. They are
+// sorted and any which overlap or touch are merged. Empty intervals are
+// discarded.
+func mkVersionSet(intervals []interval) VersionSet {
+	intervals = slices.DeleteFunc(intervals, interval.isEmpty)
+	slices.SortFunc(intervals, func(a, b interval) int {
+		return Compare(a.lo, b.lo)
+	})
+
+	vs := VersionSet{}
+
+	for _, i := range intervals {
+		last := len(vs.intervals) - 1
+		if last >= 0 && compareHi(i.lo, vs.intervals[last].hi) <= 0 {
+			if compareHi(i.hi, vs.intervals[last].hi) > 0 {
+				vs.intervals[last].hi = i.hi
+			}
+
+			continue
+		}
+
+		vs.intervals = append(vs.intervals, i)
+	}
+
+	return vs
+}
+
+// comparatorVersionSet returns the VersionSet of versions satisfying the
+// comparator
+func comparatorVersionSet(c comparator) VersionSet {
+	sv := precedenceOnly(c.sv)
+
+	switch c.op {
+	case opEQ:
+		return mkVersionSet([]interval{{lo: sv, hi: successor(sv)}})
+	case opNE:
+		return mkVersionSet([]interval{
+			{lo: minSV(), hi: sv},
+			{lo: successor(sv)},
+		})
+	case opLT:
+		return mkVersionSet([]interval{{lo: minSV(), hi: sv}})
+	case opLE:
+		return mkVersionSet([]interval{{lo: minSV(), hi: successor(sv)}})
+	case opGT:
+		return mkVersionSet([]interval{{lo: successor(sv)}})
+	case opGE:
+		return mkVersionSet([]interval{{lo: sv}})
+	}
+
+	return NoVersions()
+}
+
+// NewVersionSet returns the VersionSet of versions satisfying the
+// constraint. Note that the VersionSet does not apply the rule for
+// pre-release versions that the Constraint uses (see VersionSet).
+func NewVersionSet(c *Constraint) VersionSet {
+	vs := NoVersions()
+
+	for _, cs := range c.alts {
+		altVS := AllVersions()
+		for _, cmp := range cs {
+			altVS = altVS.Intersect(comparatorVersionSet(cmp))
+		}
+
+		vs = vs.Union(altVS)
+	}
+
+	return vs
+}
+
+// Intersect returns the VersionSet of versions in both vs and other
+func (vs VersionSet) Intersect(other VersionSet) VersionSet {
+	intervals := []interval{}
+
+	for _, a := range vs.intervals {
+		for _, b := range other.intervals {
+			i := interval{lo: a.lo, hi: a.hi}
+			if Compare(b.lo, i.lo) > 0 {
+				i.lo = b.lo
+			}
+
+			if compareHi(b.hi, i.hi) < 0 {
+				i.hi = b.hi
+			}
+
+			intervals = append(intervals, i)
+		}
+	}
+
+	return mkVersionSet(intervals)
+}
+
+// Union returns the VersionSet of versions in either vs or other
+func (vs VersionSet) Union(other VersionSet) VersionSet {
+	return mkVersionSet(slices.Concat(vs.intervals, other.intervals))
+}
+
+// Complement returns the VersionSet of versions not in vs
+func (vs VersionSet) Complement() VersionSet {
+	intervals := []interval{}
+	lo := minSV()
+
+	for _, i := range vs.intervals {
+		intervals = append(intervals, interval{lo: lo, hi: i.lo})
+		if i.hi == nil {
+			return mkVersionSet(intervals)
+		}
+
+		lo = i.hi
+	}
+
+	intervals = append(intervals, interval{lo: lo})
+
+	return mkVersionSet(intervals)
+}
+
+// IsEmpty returns true if there are no versions in the VersionSet
+func (vs VersionSet) IsEmpty() bool {
+	return len(vs.intervals) == 0
+}
+
+// Contains returns true if the SV is in the VersionSet
+func (vs VersionSet) Contains(sv *SV) bool {
+	if sv == nil {
+		return false
+	}
+
+	for _, i := range vs.intervals {
+		if i.contains(sv) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// String returns the canonical string form of the VersionSet. This is
+// written in the same form as a Constraint, with the fewest clauses needed
+// to describe the VersionSet. An empty VersionSet is shown as "<0.0.0-0"
+// and the VersionSet of all versions as ">=0.0.0-0".
+func (vs VersionSet) String() string {
+	if vs.IsEmpty() {
+		return comparator{op: opLT, sv: minSV()}.String()
+	}
+
+	parts := make([]string, 0, len(vs.intervals))
+	for _, i := range vs.intervals {
+		parts = append(parts, i.String())
+	}
+
+	return strings.Join(parts, constraintJoinAlts)
+}
@@ -0,0 +1,241 @@
+package semver_test
+
+import (
+	"testing"
+
+	"github.com/nickwells/semver.mod/v3/semver"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+// mkVersionSet parses the constraint and returns the corresponding
+// VersionSet
+func mkVersionSet(s string) semver.VersionSet {
+	return semver.NewVersionSet(semver.ParseConstraintOrPanic(s))
+}
+
+func TestNewVersionSet(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		cStr   string
+		expStr string
+	}{
+		{
+			ID:     testhelper.MkID("range"),
+			cStr:   ">=1.2.0 <2.0.0",
+			expStr: ">=1.2.0 <2.0.0",
+		},
+		{
+			ID:     testhelper.MkID("single version"),
+			cStr:   "=1.2.3",
+			expStr: "=1.2.3",
+		},
+		{
+			ID:     testhelper.MkID("single version - build IDs are dropped"),
+			cStr:   "=1.2.3-rc.1+build",
+			expStr: "=1.2.3-rc.1",
+		},
+		{
+			ID:     testhelper.MkID("not equal"),
+			cStr:   "!=1.2.3",
+			expStr: "<1.2.3 || >=1.2.4-0",
+		},
+		{
+			ID:     testhelper.MkID("less than or equal"),
+			cStr:   "<=1.2.3",
+			expStr: "<1.2.4-0",
+		},
+		{
+			ID:     testhelper.MkID("greater than (pre-release)"),
+			cStr:   ">1.2.3-rc",
+			expStr: ">=1.2.3-rc.0",
+		},
+		{
+			ID:     testhelper.MkID("redundant clauses"),
+			cStr:   ">=1.0.0 >=1.2.0 <3.0.0 <2.0.0",
+			expStr: ">=1.2.0 <2.0.0",
+		},
+		{
+			ID:     testhelper.MkID("overlapping alternatives"),
+			cStr:   "^1.2.0 || ~1.5.0 || >=1.9.0 <2.5.0",
+			expStr: ">=1.2.0 <2.5.0",
+		},
+		{
+			ID:     testhelper.MkID("touching alternatives"),
+			cStr:   "<1.0.0 || >=1.0.0 <2.0.0",
+			expStr: "<2.0.0",
+		},
+		{
+			ID:     testhelper.MkID("separate alternatives"),
+			cStr:   ">=3.0.0 || <1.0.0",
+			expStr: "<1.0.0 || >=3.0.0",
+		},
+		{
+			ID:     testhelper.MkID("empty"),
+			cStr:   ">=2.0.0 <1.0.0",
+			expStr: "<0.0.0-0",
+		},
+		{
+			ID:     testhelper.MkID("all (from the minimum version)"),
+			cStr:   ">=0.0.0-0",
+			expStr: ">=0.0.0-0",
+		},
+	}
+
+	for _, tc := range testCases {
+		testhelper.DiffString(t, tc.IDStr(), "version set",
+			mkVersionSet(tc.cStr).String(), tc.expStr)
+	}
+}
+
+func TestVersionSetAlgebra(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		a, b            string
+		expIntersection string
+		expUnion        string
+		expComplementA  string
+		expEmptyInter   bool
+	}{
+		{
+			ID:              testhelper.MkID("overlapping ranges"),
+			a:               "^1.2.0",
+			b:               ">=1.5.0 <3.0.0",
+			expIntersection: ">=1.5.0 <2.0.0",
+			expUnion:        ">=1.2.0 <3.0.0",
+			expComplementA:  "<1.2.0 || >=2.0.0",
+		},
+		{
+			ID:              testhelper.MkID("conflicting ranges"),
+			a:               "^1.2.0",
+			b:               "^2.1.0",
+			expIntersection: "<0.0.0-0",
+			expUnion:        ">=1.2.0 <2.0.0 || >=2.1.0 <3.0.0",
+			expComplementA:  "<1.2.0 || >=2.0.0",
+			expEmptyInter:   true,
+		},
+		{
+			ID:              testhelper.MkID("excluded version"),
+			a:               "^1.2.0",
+			b:               "!=1.4.0",
+			expIntersection: ">=1.2.0 <1.4.0 || >=1.4.1-0 <2.0.0",
+			expUnion:        ">=0.0.0-0",
+			expComplementA:  "<1.2.0 || >=2.0.0",
+		},
+		{
+			ID:              testhelper.MkID("single version in a range"),
+			a:               "=1.4.0",
+			b:               "^1.2.0",
+			expIntersection: "=1.4.0",
+			expUnion:        ">=1.2.0 <2.0.0",
+			expComplementA:  "<1.4.0 || >=1.4.1-0",
+		},
+		{
+			ID:              testhelper.MkID("adjacent ranges"),
+			a:               "<=1.4.0",
+			b:               ">1.4.0",
+			expIntersection: "<0.0.0-0",
+			expUnion:        ">=0.0.0-0",
+			expComplementA:  ">=1.4.1-0",
+			expEmptyInter:   true,
+		},
+		{
+			ID:              testhelper.MkID("no gap between pre-releases"),
+			a:               "<=1.4.0-a",
+			b:               ">1.4.0-a",
+			expIntersection: "<0.0.0-0",
+			expUnion:        ">=0.0.0-0",
+			expComplementA:  ">=1.4.0-a.0",
+			expEmptyInter:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		a := mkVersionSet(tc.a)
+		b := mkVersionSet(tc.b)
+
+		inter := a.Intersect(b)
+		testhelper.DiffString(t, tc.IDStr(), "intersection",
+			inter.String(), tc.expIntersection)
+		testhelper.DiffBool(t, tc.IDStr(), "intersection is empty",
+			inter.IsEmpty(), tc.expEmptyInter)
+		testhelper.DiffString(t, tc.IDStr(), "reversed intersection",
+			b.Intersect(a).String(), tc.expIntersection)
+		testhelper.DiffString(t, tc.IDStr(), "union",
+			a.Union(b).String(), tc.expUnion)
+		testhelper.DiffString(t, tc.IDStr(), "reversed union",
+			b.Union(a).String(), tc.expUnion)
+		testhelper.DiffString(t, tc.IDStr(), "complement",
+			a.Complement().String(), tc.expComplementA)
+		testhelper.DiffString(t, tc.IDStr(), "double complement",
+			a.Complement().Complement().String(), a.String())
+		testhelper.DiffBool(t, tc.IDStr(), "intersection with complement",
+			a.Intersect(a.Complement()).IsEmpty(), true)
+	}
+}
+
+func TestVersionSetContains(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		cStr        string
+		svStr       string
+		expContains bool
+	}{
+		{
+			ID:          testhelper.MkID("in range"),
+			cStr:        "^1.2.0",
+			svStr:       "v1.3.0",
+			expContains: true,
+		},
+		{
+			ID:          testhelper.MkID("pre-release in range"),
+			cStr:        "^1.2.0",
+			svStr:       "v1.3.0-rc.1",
+			expContains: true,
+		},
+		{
+			ID:    testhelper.MkID("pre-release of the upper bound"),
+			cStr:  "<2.0.0-0",
+			svStr: "v2.0.0-rc.1",
+		},
+		{
+			ID:    testhelper.MkID("below range"),
+			cStr:  "^1.2.0",
+			svStr: "v1.1.9",
+		},
+		{
+			ID:    testhelper.MkID("at the upper bound"),
+			cStr:  "^1.2.0",
+			svStr: "v2.0.0",
+		},
+		{
+			ID:          testhelper.MkID("in the second interval"),
+			cStr:        "<1.0.0 || >=3.0.0",
+			svStr:       "v3.0.0+build",
+			expContains: true,
+		},
+		{
+			ID:    testhelper.MkID("between intervals"),
+			cStr:  "<1.0.0 || >=3.0.0",
+			svStr: "v2.0.0",
+		},
+	}
+
+	for _, tc := range testCases {
+		sv, err := semver.ParseSV(tc.svStr)
+		if err != nil {
+			t.Fatal("Couldn't parse the semver: ", err)
+		}
+
+		testhelper.DiffBool(t, tc.IDStr(), "contains "+tc.svStr,
+			mkVersionSet(tc.cStr).Contains(sv), tc.expContains)
+	}
+
+	testhelper.DiffBool(t, "AllVersions", "contains v0.0.0-0",
+		semver.AllVersions().Contains(semver.NewSVOrPanic(0, 0, 0,
+			[]string{"0"}, nil)),
+		true)
+	testhelper.DiffBool(t, "NoVersions", "contains v0.0.0-0",
+		semver.NoVersions().Contains(semver.NewSVOrPanic(0, 0, 0,
+			[]string{"0"}, nil)),
+		false)
+}
@@ -0,0 +1,124 @@
+package semver
+
+// Clone returns a new SV which is a copy of the SV and shares no data with
+// it. The With... and Next... methods also return new SVs, leaving the
+// original unchanged.
+func (sv SV) Clone() *SV {
+	c := &SV{}
+	sv.CopyInto(c)
+
+	return c
+}
+
+// WithMajor returns a copy of the SV with the major version number changed.
+// It returns an error if the number is negative.
+func (sv SV) WithMajor(major int) (*SV, error) {
+	if err := checkIntVNums(major, 0, 0); err != nil {
+		return nil, err
+	}
+
+	c := sv.Clone()
+	c.major = intVNum(major)
+
+	return c, nil
+}
+
+// WithMinor returns a copy of the SV with the minor version number changed.
+// It returns an error if the number is negative.
+func (sv SV) WithMinor(minor int) (*SV, error) {
+	if err := checkIntVNums(0, minor, 0); err != nil {
+		return nil, err
+	}
+
+	c := sv.Clone()
+	c.minor = intVNum(minor)
+
+	return c, nil
+}
+
+// WithPatch returns a copy of the SV with the patch version number changed.
+// It returns an error if the number is negative.
+func (sv SV) WithPatch(patch int) (*SV, error) {
+	if err := checkIntVNums(0, 0, patch); err != nil {
+		return nil, err
+	}
+
+	c := sv.Clone()
+	c.patch = intVNum(patch)
+
+	return c, nil
+}
+
+// WithPreRelIDs returns a copy of the SV with the pre-release IDs replaced
+// by a copy of the IDs. An empty or nil slice gives a release version. It
+// returns an error if any of the IDs is not well-formed.
+func (sv SV) WithPreRelIDs(ids []string) (*SV, error) {
+	c := sv.Clone()
+	if err := c.SetPreRelIDs(ids); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// WithBuildIDs returns a copy of the SV with the build IDs replaced by a
+// copy of the IDs. An empty or nil slice removes the build IDs. It returns
+// an error if any of the IDs is not well-formed.
+func (sv SV) WithBuildIDs(ids []string) (*SV, error) {
+	c := sv.Clone()
+	if err := c.SetBuildIDs(ids); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// NextMajor returns a copy of the SV with the major version number
+// incremented as for IncrMajor
+func (sv SV) NextMajor() *SV {
+	c := sv.Clone()
+	c.IncrMajor()
+
+	return c
+}
+
+// NextMinor returns a copy of the SV with the minor version number
+// incremented as for IncrMinor
+func (sv SV) NextMinor() *SV {
+	c := sv.Clone()
+	c.IncrMinor()
+
+	return c
+}
+
+// NextPatch returns a copy of the SV with the patch version number
+// incremented as for IncrPatch
+func (sv SV) NextPatch() *SV {
+	c := sv.Clone()
+	c.IncrPatch()
+
+	return c
+}
+
+// NextPreRelease returns a copy of the SV moved on to the next pre-release
+// as for IncrPreRelease. It returns an error if the channel is not a valid
+// pre-release ID.
+func (sv SV) NextPreRelease(channel string) (*SV, error) {
+	c := sv.Clone()
+	if err := c.IncrPreRelease(channel); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Released returns a copy of the SV with the pre-release IDs removed as for
+// Release. It returns an error if the SV is not a pre-release version.
+func (sv SV) Released() (*SV, error) {
+	c := sv.Clone()
+	if err := c.Release(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
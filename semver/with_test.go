@@ -0,0 +1,179 @@
+package semver_test
+
+import (
+	"testing"
+
+	"github.com/nickwells/semver.mod/v3/semver"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestWith(t *testing.T) {
+	const origStr = "v1.2.3-rc.1+b.1"
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		with     func(semver.SV) (*semver.SV, error)
+		expSVStr string
+	}{
+		{
+			ID: testhelper.MkID("WithMajor"),
+			with: func(sv semver.SV) (*semver.SV, error) {
+				return sv.WithMajor(7)
+			},
+			expSVStr: "v7.2.3-rc.1+b.1",
+		},
+		{
+			ID: testhelper.MkID("WithMajor - negative"),
+			with: func(sv semver.SV) (*semver.SV, error) {
+				return sv.WithMajor(-1)
+			},
+			ExpErr: testhelper.MkExpErr("bad major version: -1"),
+		},
+		{
+			ID: testhelper.MkID("WithMinor"),
+			with: func(sv semver.SV) (*semver.SV, error) {
+				return sv.WithMinor(0)
+			},
+			expSVStr: "v1.0.3-rc.1+b.1",
+		},
+		{
+			ID: testhelper.MkID("WithPatch - negative"),
+			with: func(sv semver.SV) (*semver.SV, error) {
+				return sv.WithPatch(-3)
+			},
+			ExpErr: testhelper.MkExpErr("bad patch version: -3"),
+		},
+		{
+			ID: testhelper.MkID("WithPreRelIDs"),
+			with: func(sv semver.SV) (*semver.SV, error) {
+				return sv.WithPreRelIDs([]string{"beta", "2"})
+			},
+			expSVStr: "v1.2.3-beta.2+b.1",
+		},
+		{
+			ID: testhelper.MkID("WithPreRelIDs - none"),
+			with: func(sv semver.SV) (*semver.SV, error) {
+				return sv.WithPreRelIDs(nil)
+			},
+			expSVStr: "v1.2.3+b.1",
+		},
+		{
+			ID: testhelper.MkID("WithPreRelIDs - bad"),
+			with: func(sv semver.SV) (*semver.SV, error) {
+				return sv.WithPreRelIDs([]string{"01"})
+			},
+			ExpErr: testhelper.MkExpErr("the Pre-Rel ID: '01' must have"),
+		},
+		{
+			ID: testhelper.MkID("WithBuildIDs"),
+			with: func(sv semver.SV) (*semver.SV, error) {
+				return sv.WithBuildIDs([]string{"x"})
+			},
+			expSVStr: "v1.2.3-rc.1+x",
+		},
+		{
+			ID: testhelper.MkID("NextMajor"),
+			with: func(sv semver.SV) (*semver.SV, error) {
+				return sv.NextMajor(), nil
+			},
+			expSVStr: "v2.0.0+b.1",
+		},
+		{
+			ID: testhelper.MkID("NextMinor"),
+			with: func(sv semver.SV) (*semver.SV, error) {
+				return sv.NextMinor(), nil
+			},
+			expSVStr: "v1.3.0+b.1",
+		},
+		{
+			ID: testhelper.MkID("NextPatch"),
+			with: func(sv semver.SV) (*semver.SV, error) {
+				return sv.NextPatch(), nil
+			},
+			expSVStr: "v1.2.4+b.1",
+		},
+		{
+			ID: testhelper.MkID("NextPreRelease"),
+			with: func(sv semver.SV) (*semver.SV, error) {
+				return sv.NextPreRelease("")
+			},
+			expSVStr: "v1.2.3-rc.2+b.1",
+		},
+		{
+			ID: testhelper.MkID("Released"),
+			with: func(sv semver.SV) (*semver.SV, error) {
+				return sv.Released()
+			},
+			expSVStr: "v1.2.3+b.1",
+		},
+		{
+			ID: testhelper.MkID("Clone"),
+			with: func(sv semver.SV) (*semver.SV, error) {
+				return sv.Clone(), nil
+			},
+			expSVStr: origStr,
+		},
+	}
+
+	for _, tc := range testCases {
+		orig, err := semver.ParseSV(origStr)
+		if err != nil {
+			t.Fatal("Couldn't parse the semver: ", err)
+		}
+
+		sv, err := tc.with(*orig)
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			testhelper.DiffString(t, tc.IDStr(), "new SV",
+				sv.String(), tc.expSVStr)
+		}
+
+		testhelper.DiffString(t, tc.IDStr(), "original SV",
+			orig.String(), origStr)
+	}
+}
+
+func TestNoAliasing(t *testing.T) {
+	prIDs := []string{"rc", "1"}
+	buildIDs := []string{"b"}
+
+	sv := semver.NewSVOrPanic(1, 2, 3, prIDs, buildIDs)
+
+	prIDs[0] = "changed"
+	buildIDs[0] = "changed"
+
+	testhelper.DiffString(t, "NewSV", "after changing the args",
+		sv.String(), "v1.2.3-rc.1+b")
+
+	sv.PreRelIDs()[0] = "changed"
+	sv.BuildIDs()[0] = "changed"
+
+	testhelper.DiffString(t, "getters", "after changing the results",
+		sv.String(), "v1.2.3-rc.1+b")
+
+	ids := []string{"alpha"}
+	if err := sv.SetPreRelIDs(ids); err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+
+	if err := sv.SetBuildIDs(ids); err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+
+	ids[0] = "changed"
+
+	testhelper.DiffString(t, "setters", "after changing the args",
+		sv.String(), "v1.2.3-alpha+alpha")
+
+	next, err := sv.WithPreRelIDs([]string{"beta"})
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+
+	if err := next.SetBuildIDs([]string{"other"}); err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+
+	testhelper.DiffString(t, "With...", "original after changing the new SV",
+		sv.String(), "v1.2.3-alpha+alpha")
+}